@@ -0,0 +1,339 @@
+package auth
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoginPolicy configures how failed logins are throttled.
+type LoginPolicy struct {
+	// FreeAttempts is the number of failures allowed before backing off.
+	FreeAttempts int
+	// BaseDelay is the delay after the first failure past FreeAttempts, it
+	// doubles with every further failure up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockoutThreshold is the number of failures that locks logins out for
+	// LockoutDuration.
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	// ResetAfter is how long failures are remembered for, they are never
+	// forgotten when it is zero.
+	ResetAfter time.Duration
+}
+
+// Lockout is reported when an email, ip address or second factor challenge
+// gets locked out.
+type Lockout struct {
+	// Key is either email, ip or challenge.
+	Key      string
+	Value    string
+	Failures int
+	Until    time.Time
+}
+
+type loginFailures struct {
+	count        int
+	lastFailure  time.Time
+	blockedUntil time.Time
+	// inFlight is the number of reserved attempts that have not completed yet.
+	inFlight int
+}
+
+// inFlightRetryAfter is how long clients are asked to wait when an attempt is
+// refused because of the attempts that are still in flight.
+const inFlightRetryAfter = time.Second
+
+// defaultChallengeTTL is how long a second factor challenge is remembered for
+// when it does not expire.
+const defaultChallengeTTL = 10 * time.Minute
+
+// LoginGuard tracks failed logins per email and per ip address in memory.
+// Failed second factor codes are tracked per challenge and count against the
+// email the challenge was issued for too, the challenge policy is the email's.
+type LoginGuard struct {
+	Email LoginPolicy
+	IP    LoginPolicy
+
+	mu         sync.Mutex
+	failures   map[string]*loginFailures
+	challenges map[string]challenge
+	lastSweep  time.Time
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// challenge is a second factor challenge issued after a correct password.
+type challenge struct {
+	email     string
+	expiresAt time.Time
+}
+
+// NewLoginGuard returns a LoginGuard that throttles emails and ip addresses
+// with the provided policies.
+func NewLoginGuard(email, ip LoginPolicy) *LoginGuard {
+	return &LoginGuard{
+		Email:      email,
+		IP:         ip,
+		failures:   map[string]*loginFailures{},
+		challenges: map[string]challenge{},
+		lastSweep:  time.Now(),
+		now:        time.Now,
+	}
+}
+
+// LoginAttempt is a login attempt reserved with Reserve or ReserveSecondFactor,
+// it must be completed with one of Failed, Succeeded, Challenged or Cancel.
+type LoginAttempt struct {
+	guard *LoginGuard
+	email string
+	ip    string
+	// challenge is set for second factor attempts.
+	challenge string
+	done      bool
+}
+
+// Reserve reserves a login attempt for email from ip. When the client has to
+// wait before it can try again no attempt is reserved and the wait is returned.
+// Attempts that are still in flight count as failures so that concurrent
+// attempts can not get past the free attempts or the lockout threshold.
+func (g *LoginGuard) Reserve(email, ip string) (*LoginAttempt, time.Duration) {
+	return g.reserve(&LoginAttempt{guard: g, email: email, ip: ip})
+}
+
+// ReserveSecondFactor reserves an attempt at the code of challengeID from ip,
+// it counts against the email the challenge was issued for when the challenge
+// was issued by this guard.
+func (g *LoginGuard) ReserveSecondFactor(challengeID, ip string) (*LoginAttempt, time.Duration) {
+	g.mu.Lock()
+	email := g.challenges[challengeID].email
+	g.mu.Unlock()
+	return g.reserve(&LoginAttempt{guard: g, email: email, ip: ip, challenge: challengeID})
+}
+
+func (g *LoginGuard) reserve(attempt *LoginAttempt) (*LoginAttempt, time.Duration) {
+	now := g.now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sweep(now)
+	var wait time.Duration
+	for _, key := range attempt.keys() {
+		policy := g.policy(key)
+		if d := reserveWait(g.entry(key, policy, now), policy, now); d > wait {
+			wait = d
+		}
+	}
+	if wait > 0 {
+		return nil, wait
+	}
+	for _, key := range attempt.keys() {
+		g.failures[key].inFlight++
+	}
+	return attempt, 0
+}
+
+// keys returns the keys the attempt's failures are tracked with.
+func (a *LoginAttempt) keys() []string {
+	var keys []string
+	if a.email != "" {
+		keys = append(keys, emailKey(a.email))
+	}
+	keys = append(keys, ipKey(a.ip))
+	if a.challenge != "" {
+		keys = append(keys, challengeKey(a.challenge))
+	}
+	return keys
+}
+
+// Failed records the attempt as a failed login and returns the lockouts it caused.
+func (a *LoginAttempt) Failed() []Lockout {
+	g := a.guard
+	now := g.now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !a.release() {
+		return nil
+	}
+	var lockouts []Lockout
+	for _, key := range a.keys() {
+		if lockout := g.fail(key, g.policy(key), now); lockout != nil {
+			lockout.Key, lockout.Value = splitKey(key)
+			lockouts = append(lockouts, *lockout)
+		}
+	}
+	return lockouts
+}
+
+// Succeeded forgets the failed logins for the attempt's email and challenge.
+// The failures from the ip address are kept so that logging into another
+// account does not reset them.
+func (a *LoginAttempt) Succeeded() {
+	g := a.guard
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !a.release() {
+		return
+	}
+	if a.email != "" {
+		g.forget(emailKey(a.email))
+	}
+	if a.challenge != "" {
+		g.forget(challengeKey(a.challenge))
+		delete(g.challenges, a.challenge)
+	}
+}
+
+// Challenged completes an attempt whose password was correct but that still
+// has to pass the second factor challenge. The email's failures are kept until
+// the challenge succeeds, and the challenge's failures count against the email.
+func (a *LoginAttempt) Challenged(challengeID string, expiresAt time.Time) {
+	g := a.guard
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !a.release() || challengeID == "" {
+		return
+	}
+	if expiresAt.IsZero() {
+		expiresAt = g.now().Add(defaultChallengeTTL)
+	}
+	g.challenges[challengeID] = challenge{email: a.email, expiresAt: expiresAt}
+}
+
+// Cancel releases an attempt that neither failed nor succeeded, e.g when the
+// user service could not be reached.
+func (a *LoginAttempt) Cancel() {
+	a.guard.mu.Lock()
+	a.release()
+	a.guard.mu.Unlock()
+}
+
+// release reports whether the attempt was still in flight, it must be called
+// with the guard's mutex held.
+func (a *LoginAttempt) release() bool {
+	if a.done {
+		return false
+	}
+	a.done = true
+	for _, key := range a.keys() {
+		if f, ok := a.guard.failures[key]; ok {
+			f.inFlight--
+		}
+	}
+	return true
+}
+
+// forget resets the failures recorded for key, it must be called with the
+// guard's mutex held.
+func (g *LoginGuard) forget(key string) {
+	if f, ok := g.failures[key]; ok {
+		f.count = 0
+		f.blockedUntil = time.Time{}
+	}
+}
+
+func (g *LoginGuard) policy(key string) LoginPolicy {
+	if strings.HasPrefix(key, "ip:") {
+		return g.IP
+	}
+	return g.Email
+}
+
+// entry returns the failures recorded for key, the failures the policy no
+// longer remembers are reset.
+func (g *LoginGuard) entry(key string, policy LoginPolicy, now time.Time) *loginFailures {
+	f, ok := g.failures[key]
+	if !ok {
+		f = &loginFailures{}
+		g.failures[key] = f
+	}
+	if f.count > 0 && forgotten(f, policy, now) && !f.blockedUntil.After(now) {
+		f.count = 0
+	}
+	return f
+}
+
+func reserveWait(f *loginFailures, policy LoginPolicy, now time.Time) time.Duration {
+	if f.blockedUntil.After(now) {
+		return f.blockedUntil.Sub(now)
+	}
+	if f.inFlight == 0 {
+		return 0
+	}
+	// the attempts in flight could still fail and block this one.
+	attempts := f.count + f.inFlight
+	if policy.LockoutThreshold > 0 && attempts >= policy.LockoutThreshold {
+		return inFlightRetryAfter
+	}
+	if policy.BaseDelay > 0 && attempts >= policy.FreeAttempts {
+		return inFlightRetryAfter
+	}
+	return 0
+}
+
+func (g *LoginGuard) fail(key string, policy LoginPolicy, now time.Time) *Lockout {
+	f := g.entry(key, policy, now)
+	f.count++
+	f.lastFailure = now
+	if policy.LockoutThreshold > 0 && f.count >= policy.LockoutThreshold {
+		// failures past the threshold lock logins out again once the
+		// previous lockout has expired.
+		f.blockedUntil = now.Add(policy.LockoutDuration)
+		return &Lockout{Failures: f.count, Until: f.blockedUntil}
+	}
+	if f.count > policy.FreeAttempts && policy.BaseDelay > 0 {
+		delay := policy.BaseDelay << uint(f.count-policy.FreeAttempts-1)
+		if delay <= 0 || delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+		f.blockedUntil = now.Add(delay)
+	}
+	return nil
+}
+
+// sweep removes the failures that are no longer remembered atmost once a minute.
+func (g *LoginGuard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < time.Minute {
+		return
+	}
+	for key, f := range g.failures {
+		policy := g.policy(key)
+		if f.inFlight == 0 && (f.count == 0 || forgotten(f, policy, now)) && !f.blockedUntil.After(now) {
+			delete(g.failures, key)
+		}
+	}
+	for challengeID, c := range g.challenges {
+		if c.expiresAt.Before(now) {
+			delete(g.challenges, challengeID)
+		}
+	}
+	g.lastSweep = now
+}
+
+// forgotten reports whether the failures are older than the policy remembers
+// them for, failures are never forgotten when ResetAfter is zero.
+func forgotten(f *loginFailures, policy LoginPolicy, now time.Time) bool {
+	return policy.ResetAfter > 0 && now.Sub(f.lastFailure) > policy.ResetAfter
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func emailKey(email string) string {
+	return "email:" + normalizeEmail(email)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func challengeKey(challengeID string) string {
+	return "challenge:" + challengeID
+}
+
+// splitKey returns the kind and the value of a failures key.
+func splitKey(key string) (string, string) {
+	i := strings.Index(key, ":")
+	return key[:i], key[i+1:]
+}
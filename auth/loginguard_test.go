@@ -0,0 +1,279 @@
+package auth
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testEmail = "ada@example.com"
+	testIP    = "203.0.113.7"
+)
+
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+// newTestLoginGuard returns a guard that throttles emails with policy, ip
+// addresses are not throttled.
+func newTestLoginGuard(policy LoginPolicy) (*LoginGuard, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)}
+	guard := NewLoginGuard(policy, LoginPolicy{})
+	guard.now = clock.now
+	return guard, clock
+}
+
+// failLogins records n failed logins for email, the clock is moved past the
+// delays that refuse them.
+func failLogins(t *testing.T, guard *LoginGuard, clock *fakeClock, email string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		attempt, wait := guard.Reserve(email, testIP)
+		if attempt == nil {
+			clock.advance(wait)
+			if attempt, wait = guard.Reserve(email, testIP); attempt == nil {
+				t.Fatalf("Reserve() refused after waiting, wait = %v", wait)
+			}
+		}
+		attempt.Failed()
+	}
+}
+
+func TestLoginGuardReserve(t *testing.T) {
+	backoff := LoginPolicy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	lockout := LoginPolicy{LockoutThreshold: 5, LockoutDuration: 15 * time.Minute}
+	tests := []struct {
+		name     string
+		policy   LoginPolicy
+		failures int
+		// advance moves the clock after the failures, failuresAfter more
+		// failures are then recorded.
+		advance       time.Duration
+		failuresAfter int
+		wantWait      time.Duration
+	}{
+		{
+			name:     "allows the free attempts",
+			policy:   backoff,
+			failures: 3,
+			wantWait: 0,
+		},
+		{
+			name:     "delays the first failure past the free attempts",
+			policy:   backoff,
+			failures: 4,
+			wantWait: time.Second,
+		},
+		{
+			name:     "doubles the delay with every failure",
+			policy:   backoff,
+			failures: 6,
+			wantWait: 4 * time.Second,
+		},
+		{
+			name:     "caps the delay",
+			policy:   backoff,
+			failures: 7,
+			wantWait: 5 * time.Second,
+		},
+		{
+			name:     "caps delays that overflow",
+			policy:   backoff,
+			failures: 100,
+			wantWait: 5 * time.Second,
+		},
+		{
+			name:     "allows attempts once the delay is over",
+			policy:   backoff,
+			failures: 5,
+			advance:  2 * time.Second,
+			wantWait: 0,
+		},
+		{
+			name:     "locks out at the threshold",
+			policy:   lockout,
+			failures: 5,
+			wantWait: 15 * time.Minute,
+		},
+		{
+			name:     "allows attempts once the lockout expires",
+			policy:   lockout,
+			failures: 5,
+			advance:  15 * time.Minute,
+			wantWait: 0,
+		},
+		{
+			name:          "locks out again after the lockout expires",
+			policy:        lockout,
+			failures:      5,
+			advance:       15 * time.Minute,
+			failuresAfter: 1,
+			wantWait:      15 * time.Minute,
+		},
+		{
+			name: "forgets failures after the reset period",
+			policy: LoginPolicy{
+				FreeAttempts:     3,
+				BaseDelay:        time.Second,
+				MaxDelay:         time.Minute,
+				LockoutThreshold: 5,
+				LockoutDuration:  15 * time.Minute,
+				ResetAfter:       time.Hour,
+			},
+			failures:      4,
+			advance:       2 * time.Hour,
+			failuresAfter: 3,
+			wantWait:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard, clock := newTestLoginGuard(tt.policy)
+			failLogins(t, guard, clock, testEmail, tt.failures)
+			clock.advance(tt.advance)
+			failLogins(t, guard, clock, testEmail, tt.failuresAfter)
+			attempt, wait := guard.Reserve(testEmail, testIP)
+			if wait != tt.wantWait {
+				t.Fatalf("Reserve() wait = %v, want %v", wait, tt.wantWait)
+			}
+			if (attempt == nil) != (tt.wantWait > 0) {
+				t.Fatalf("Reserve() attempt = %v, want an attempt only without a wait", attempt)
+			}
+		})
+	}
+}
+
+func TestLoginGuardLockoutReport(t *testing.T) {
+	guard, clock := newTestLoginGuard(LoginPolicy{LockoutThreshold: 2, LockoutDuration: time.Minute})
+	failLogins(t, guard, clock, testEmail, 1)
+	attempt, _ := guard.Reserve(" Ada@Example.com", testIP)
+	lockouts := attempt.Failed()
+	want := Lockout{Key: "email", Value: testEmail, Failures: 2, Until: clock.now().Add(time.Minute)}
+	if len(lockouts) != 1 || lockouts[0] != want {
+		t.Fatalf("Failed() = %+v, want [%+v]", lockouts, want)
+	}
+}
+
+func TestLoginGuardConcurrentReservations(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy LoginPolicy
+		// failures are recorded before the concurrent reservations.
+		failures     int
+		wantReserved int
+	}{
+		{
+			name:         "reserves no more than the free attempts",
+			policy:       LoginPolicy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute},
+			wantReserved: 3,
+		},
+		{
+			name:         "counts the recorded failures",
+			policy:       LoginPolicy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute},
+			failures:     2,
+			wantReserved: 1,
+		},
+		{
+			name:         "reserves no more than the lockout threshold",
+			policy:       LoginPolicy{LockoutThreshold: 4, LockoutDuration: time.Minute},
+			wantReserved: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard, clock := newTestLoginGuard(tt.policy)
+			failLogins(t, guard, clock, testEmail, tt.failures)
+
+			var wg sync.WaitGroup
+			attempts := make(chan *LoginAttempt, 20)
+			for i := 0; i < cap(attempts); i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if attempt, wait := guard.Reserve(testEmail, testIP); attempt != nil {
+						attempts <- attempt
+					} else if wait != inFlightRetryAfter {
+						t.Errorf("Reserve() wait = %v, want %v", wait, inFlightRetryAfter)
+					}
+				}()
+			}
+			wg.Wait()
+			close(attempts)
+			if len(attempts) != tt.wantReserved {
+				t.Fatalf("reserved %d attempts, want %d", len(attempts), tt.wantReserved)
+			}
+
+			// cancelled attempts give their reservations back.
+			for attempt := range attempts {
+				attempt.Cancel()
+			}
+			if attempt, wait := guard.Reserve(testEmail, testIP); attempt == nil {
+				t.Fatalf("Reserve() after cancelling wait = %v, want an attempt", wait)
+			}
+		})
+	}
+}
+
+func TestLoginGuardSucceeded(t *testing.T) {
+	guard, clock := newTestLoginGuard(LoginPolicy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour})
+	guard.IP = LoginPolicy{FreeAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	failLogins(t, guard, clock, testEmail, 3)
+	clock.advance(time.Hour)
+	attempt, _ := guard.Reserve(testEmail, testIP)
+	attempt.Succeeded()
+
+	if attempt, wait := guard.Reserve(testEmail, "198.51.100.1"); attempt == nil {
+		t.Fatalf("Reserve() from another ip wait = %v, want the email's failures forgotten", wait)
+	} else {
+		attempt.Cancel()
+	}
+	// the ip address keeps its failures.
+	failLogins(t, guard, clock, "eve@example.com", 1)
+	if _, wait := guard.Reserve("bob@example.com", testIP); wait != time.Minute {
+		t.Fatalf("Reserve() from the same ip wait = %v, want %v", wait, time.Minute)
+	}
+}
+
+func TestLoginGuardSecondFactor(t *testing.T) {
+	guard, clock := newTestLoginGuard(LoginPolicy{LockoutThreshold: 3, LockoutDuration: time.Minute})
+	attempt, _ := guard.Reserve(testEmail, testIP)
+	attempt.Challenged("challenge-1", clock.now().Add(5*time.Minute))
+
+	for i := 0; i < 2; i++ {
+		attempt, wait := guard.ReserveSecondFactor("challenge-1", testIP)
+		if attempt == nil {
+			t.Fatalf("ReserveSecondFactor() wait = %v, want an attempt", wait)
+		}
+		attempt.Failed()
+	}
+	// the wrong codes count against the email.
+	failLogins(t, guard, clock, testEmail, 1)
+	if _, wait := guard.ReserveSecondFactor("challenge-1", testIP); wait != time.Minute {
+		t.Fatalf("ReserveSecondFactor() wait = %v, want %v", wait, time.Minute)
+	}
+	if _, wait := guard.Reserve(testEmail, testIP); wait != time.Minute {
+		t.Fatalf("Reserve() wait = %v, want %v", wait, time.Minute)
+	}
+
+	clock.advance(time.Minute)
+	attempt, _ = guard.ReserveSecondFactor("challenge-1", testIP)
+	attempt.Succeeded()
+	failLogins(t, guard, clock, testEmail, 2)
+	if attempt, wait := guard.Reserve(testEmail, testIP); attempt == nil {
+		t.Fatalf("Reserve() wait = %v, want the email's failures forgotten", wait)
+	}
+}
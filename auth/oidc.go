@@ -0,0 +1,319 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	// ErrUnknownProvider is returned for identity providers that are not configured.
+	ErrUnknownProvider = errors.New("unknown identity provider")
+	// ErrInvalidRedirectURI is returned for redirect uris the provider is not configured to allow.
+	ErrInvalidRedirectURI = errors.New("redirect uri is not allowed")
+	// ErrProviderExchange is returned when the provider rejects the authorization code
+	// or returns an identity that cannot be trusted.
+	ErrProviderExchange = errors.New("identity provider exchange failed")
+)
+
+// ProviderConfig configures an OpenID Connect identity provider. When Issuer is
+// set, endpoints that are left empty are read from the issuer's discovery document.
+// Providers without an id token (e.g github) only need TokenURL and UserInfoURL.
+type ProviderConfig struct {
+	Name         string
+	ClientID     string
+	ClientSecret string
+	Issuer       string
+	TokenURL     string
+	UserInfoURL  string
+	JWKSURL      string
+	RedirectURIs []string
+}
+
+// Identity is the user identity returned by a provider.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	FullName      string
+	EmailVerified bool
+}
+
+// Providers are the configured identity providers, indexed by name.
+type Providers map[string]*Provider
+
+// Get returns the provider registered as name.
+func (p Providers) Get(name string) (*Provider, error) {
+	provider, ok := p[strings.ToLower(name)]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// Provider exchanges authorization codes for user identities.
+type Provider struct {
+	cfg    ProviderConfig
+	client *http.Client
+	keys   *keySet
+	parser *jwt.Parser
+}
+
+// NewProvider creates the provider, fetching the discovery document and the
+// provider's signing keys when they are needed.
+func NewProvider(ctx context.Context, cfg ProviderConfig) (*Provider, error) {
+	p := &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   &keySet{keys: map[string]crypto.PublicKey{}},
+		parser: jwt.NewParser(jwt.WithValidMethods([]string{
+			"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512",
+		})),
+	}
+	if cfg.Issuer != "" && (p.cfg.TokenURL == "" || p.cfg.UserInfoURL == "" || p.cfg.JWKSURL == "") {
+		if err := p.discover(ctx); err != nil {
+			return nil, fmt.Errorf("unable to discover %s endpoints: %w", cfg.Name, err)
+		}
+	}
+	if p.cfg.TokenURL == "" {
+		return nil, fmt.Errorf("%s has no token endpoint", cfg.Name)
+	}
+	if p.cfg.JWKSURL == "" && p.cfg.UserInfoURL == "" {
+		return nil, fmt.Errorf("%s needs either a jwks or a userinfo endpoint", cfg.Name)
+	}
+	if p.cfg.JWKSURL != "" {
+		keys, err := fetchJWKS(ctx, p.client, p.cfg.JWKSURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch %s jwks: %w", cfg.Name, err)
+		}
+		p.keys.set(keys)
+	}
+	return p, nil
+}
+
+func (p *Provider) discover(ctx context.Context) error {
+	var document struct {
+		TokenEndpoint    string `json:"token_endpoint"`
+		UserInfoEndpoint string `json:"userinfo_endpoint"`
+		JWKSURI          string `json:"jwks_uri"`
+	}
+	discoveryURL := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, discoveryURL, "", &document); err != nil {
+		return err
+	}
+	if p.cfg.TokenURL == "" {
+		p.cfg.TokenURL = document.TokenEndpoint
+	}
+	if p.cfg.UserInfoURL == "" {
+		p.cfg.UserInfoURL = document.UserInfoEndpoint
+	}
+	if p.cfg.JWKSURL == "" {
+		p.cfg.JWKSURL = document.JWKSURI
+	}
+	return nil
+}
+
+// Exchange redeems the authorization code issued for redirectURI and returns
+// the identity of the user that authorized it.
+func (p *Provider) Exchange(ctx context.Context, code, redirectURI string) (*Identity, error) {
+	if !p.allowsRedirectURI(redirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+	tokens, err := p.redeemCode(ctx, code, redirectURI)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderExchange, err)
+	}
+	identity := &Identity{Provider: p.cfg.Name}
+	if tokens.IDToken != "" && p.cfg.JWKSURL != "" {
+		if err := p.verifyIDToken(ctx, tokens.IDToken, identity); err != nil {
+			return nil, fmt.Errorf("%w: invalid id token: %v", ErrProviderExchange, err)
+		}
+	}
+	if (identity.Subject == "" || identity.Email == "") && p.cfg.UserInfoURL != "" {
+		if err := p.fetchUserInfo(ctx, tokens.AccessToken, identity); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrProviderExchange, err)
+		}
+	}
+	if identity.Subject == "" || identity.Email == "" {
+		return nil, fmt.Errorf("%w: provider did not return the user's id and email", ErrProviderExchange)
+	}
+	return identity, nil
+}
+
+func (p *Provider) allowsRedirectURI(redirectURI string) bool {
+	if redirectURI == "" {
+		return false
+	}
+	// the provider validates the redirect uri against the ones registered for
+	// the client, an empty allowlist defers to it.
+	if len(p.cfg.RedirectURIs) == 0 {
+		return true
+	}
+	for _, allowed := range p.cfg.RedirectURIs {
+		if redirectURI == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (p *Provider) redeemCode(ctx context.Context, code, redirectURI string) (*tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	tokens := &tokenResponse{}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(tokens); err != nil {
+		return nil, fmt.Errorf("unexpected token response status %d", res.StatusCode)
+	}
+	// some providers report errors with a 200 status.
+	if tokens.Error != "" {
+		return nil, fmt.Errorf("%s: %s", tokens.Error, tokens.ErrorDescription)
+	}
+	if res.StatusCode != http.StatusOK || tokens.AccessToken == "" {
+		return nil, fmt.Errorf("unexpected token response status %d", res.StatusCode)
+	}
+	return tokens, nil
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"`
+	Name          string      `json:"name"`
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, idToken string, identity *Identity) error {
+	claims := &idTokenClaims{}
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := p.keys.get(kid)
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		return key, nil
+	}
+	_, err := p.parser.ParseWithClaims(idToken, claims, keyFunc)
+	if errors.Is(err, ErrUnknownKey) {
+		// the provider may have rotated its keys since they were fetched.
+		keys, fetchErr := fetchJWKS(ctx, p.client, p.cfg.JWKSURL)
+		if fetchErr != nil {
+			return fetchErr
+		}
+		p.keys.set(keys)
+		claims = &idTokenClaims{}
+		_, err = p.parser.ParseWithClaims(idToken, claims, keyFunc)
+	}
+	if err != nil {
+		return err
+	}
+	if p.cfg.Issuer != "" && !claims.VerifyIssuer(p.cfg.Issuer, true) {
+		return errors.New("unexpected issuer")
+	}
+	if !claims.VerifyAudience(p.cfg.ClientID, true) {
+		return errors.New("token was not issued for this client")
+	}
+	identity.Subject = claims.Subject
+	identity.Email = claims.Email
+	identity.FullName = claims.Name
+	identity.EmailVerified = parseBoolClaim(claims.EmailVerified)
+	return nil
+}
+
+func (p *Provider) fetchUserInfo(ctx context.Context, accessToken string, identity *Identity) error {
+	var info map[string]interface{}
+	if err := p.getJSON(ctx, p.cfg.UserInfoURL, accessToken, &info); err != nil {
+		return fmt.Errorf("unable to fetch userinfo: %w", err)
+	}
+	subject := stringClaim(info, "sub")
+	if subject == "" {
+		// github identifies users with a numeric id instead of sub.
+		subject = stringClaim(info, "id")
+	}
+	if identity.Subject != "" && subject != identity.Subject {
+		return errors.New("userinfo subject does not match the id token")
+	}
+	identity.Subject = subject
+	if identity.Email == "" {
+		identity.Email = stringClaim(info, "email")
+		identity.EmailVerified = parseBoolClaim(info["email_verified"])
+	}
+	if identity.FullName == "" {
+		identity.FullName = stringClaim(info, "name")
+	}
+	return nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url, accessToken string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, res.Body)
+		return fmt.Errorf("unexpected response status %d", res.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(v)
+}
+
+func stringClaim(claims map[string]interface{}, name string) string {
+	switch value := claims[name].(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
+// parseBoolClaim handles providers that send email_verified as a string.
+func parseBoolClaim(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
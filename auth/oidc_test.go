@@ -0,0 +1,244 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testClientID     = "client-id"
+	testClientSecret = "client-secret"
+	testRedirectURI  = "https://shop.example.com/callback"
+	testCode         = "auth-code"
+	testAccessToken  = "access-token"
+)
+
+// mockIdP is an identity provider that issues an id token signed with key for
+// testCode, userInfo is served when it is set.
+type mockIdP struct {
+	*httptest.Server
+	key           *rsa.PrivateKey
+	idTokenClaims jwt.MapClaims
+	userInfo      map[string]interface{}
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, r *http.Request) {
+		writeJSON(rw, map[string]string{
+			"issuer":            idp.URL,
+			"token_endpoint":    idp.URL + "/token",
+			"userinfo_endpoint": idp.URL + "/userinfo",
+			"jwks_uri":          idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(rw http.ResponseWriter, r *http.Request) {
+		writeJSON(rw, map[string]interface{}{"keys": []jwk{{
+			Kty: "RSA",
+			Kid: "key-1",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(rw http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		if r.PostForm.Get("code") != testCode || r.PostForm.Get("redirect_uri") != testRedirectURI ||
+			r.PostForm.Get("client_id") != testClientID || r.PostForm.Get("client_secret") != testClientSecret {
+			rw.WriteHeader(http.StatusBadRequest)
+			writeJSON(rw, map[string]string{"error": "invalid_grant", "error_description": "invalid code"})
+			return
+		}
+		res := map[string]string{"access_token": testAccessToken}
+		if idp.idTokenClaims != nil {
+			res["id_token"] = idp.signIDToken(t)
+		}
+		writeJSON(rw, res)
+	})
+	mux.HandleFunc("/userinfo", func(rw http.ResponseWriter, r *http.Request) {
+		if idp.userInfo == nil || r.Header.Get("Authorization") != "Bearer "+testAccessToken {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(rw, idp.userInfo)
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func (idp *mockIdP) signIDToken(t *testing.T) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.idTokenClaims)
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func (idp *mockIdP) validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            idp.URL,
+		"aud":            testClientID,
+		"sub":            "user-1",
+		"email":          "ada@example.com",
+		"email_verified": true,
+		"name":           "Ada Lovelace",
+		"exp":            time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(v)
+}
+
+func TestProviderExchange(t *testing.T) {
+	tests := []struct {
+		name string
+		// configure sets up the idp, the provider is discovered from its issuer
+		// unless it returns a config.
+		configure func(idp *mockIdP) *ProviderConfig
+		code      string
+		want      *Identity
+		wantErr   error
+	}{
+		{
+			name: "verifies the id token",
+			configure: func(idp *mockIdP) *ProviderConfig {
+				idp.idTokenClaims = idp.validClaims()
+				return nil
+			},
+			want: &Identity{Provider: "mock", Subject: "user-1", Email: "ada@example.com", FullName: "Ada Lovelace", EmailVerified: true},
+		},
+		{
+			name: "rejects invalid codes",
+			configure: func(idp *mockIdP) *ProviderConfig {
+				idp.idTokenClaims = idp.validClaims()
+				return nil
+			},
+			code:    "invalid-code",
+			wantErr: ErrProviderExchange,
+		},
+		{
+			name: "rejects id tokens issued for another client",
+			configure: func(idp *mockIdP) *ProviderConfig {
+				idp.idTokenClaims = idp.validClaims()
+				idp.idTokenClaims["aud"] = "another-client"
+				return nil
+			},
+			wantErr: ErrProviderExchange,
+		},
+		{
+			name: "rejects id tokens from another issuer",
+			configure: func(idp *mockIdP) *ProviderConfig {
+				idp.idTokenClaims = idp.validClaims()
+				idp.idTokenClaims["iss"] = "https://attacker.example.com"
+				return nil
+			},
+			wantErr: ErrProviderExchange,
+		},
+		{
+			name: "falls back to userinfo when the id token has no email",
+			configure: func(idp *mockIdP) *ProviderConfig {
+				idp.idTokenClaims = idp.validClaims()
+				delete(idp.idTokenClaims, "email")
+				idp.userInfo = map[string]interface{}{"sub": "user-1", "email": "ada@example.com", "email_verified": "true"}
+				return nil
+			},
+			want: &Identity{Provider: "mock", Subject: "user-1", Email: "ada@example.com", FullName: "Ada Lovelace", EmailVerified: true},
+		},
+		{
+			name: "rejects a userinfo subject that does not match the id token",
+			configure: func(idp *mockIdP) *ProviderConfig {
+				idp.idTokenClaims = idp.validClaims()
+				delete(idp.idTokenClaims, "email")
+				idp.userInfo = map[string]interface{}{"sub": "user-2", "email": "eve@example.com"}
+				return nil
+			},
+			wantErr: ErrProviderExchange,
+		},
+		{
+			name: "reads numeric ids from userinfo without an id token",
+			configure: func(idp *mockIdP) *ProviderConfig {
+				idp.userInfo = map[string]interface{}{"id": 583231, "email": "ada@example.com", "name": "Ada"}
+				return &ProviderConfig{
+					TokenURL:    idp.URL + "/token",
+					UserInfoURL: idp.URL + "/userinfo",
+				}
+			},
+			want: &Identity{Provider: "mock", Subject: "583231", Email: "ada@example.com", FullName: "Ada"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newMockIdP(t)
+			cfg := tt.configure(idp)
+			if cfg == nil {
+				cfg = &ProviderConfig{Issuer: idp.URL}
+			}
+			cfg.Name = "mock"
+			cfg.ClientID = testClientID
+			cfg.ClientSecret = testClientSecret
+			cfg.RedirectURIs = []string{testRedirectURI}
+			provider, err := NewProvider(context.Background(), *cfg)
+			if err != nil {
+				t.Fatalf("NewProvider() error = %v", err)
+			}
+			code := tt.code
+			if code == "" {
+				code = testCode
+			}
+			identity, err := provider.Exchange(context.Background(), code, testRedirectURI)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Exchange() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+			if *identity != *tt.want {
+				t.Errorf("Exchange() = %+v, want %+v", identity, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderExchangeRejectsUnknownRedirectURI(t *testing.T) {
+	idp := newMockIdP(t)
+	provider, err := NewProvider(context.Background(), ProviderConfig{
+		Name:         "mock",
+		ClientID:     testClientID,
+		Issuer:       idp.URL,
+		RedirectURIs: []string{testRedirectURI},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	_, err = provider.Exchange(context.Background(), testCode, "https://attacker.example.com/callback")
+	if !errors.Is(err, ErrInvalidRedirectURI) {
+		t.Fatalf("Exchange() error = %v, want %v", err, ErrInvalidRedirectURI)
+	}
+}
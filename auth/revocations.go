@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Revocation revokes the token with TokenID or, when TokenID is empty, every
+// token of UserID issued before IssuedBefore.
+type Revocation struct {
+	TokenID      string
+	UserID       string
+	IssuedBefore time.Time
+	ExpiresAt    time.Time
+}
+
+// RevocationSource returns the revocations recorded after since.
+type RevocationSource func(ctx context.Context, since time.Time) ([]Revocation, error)
+
+// revocationList is the local copy of the shared denylist, it is synced in the
+// background so that verifying a token never waits for the source.
+type revocationList struct {
+	mu           sync.RWMutex
+	tokens       map[string]time.Time
+	users        map[string]Revocation
+	lastSyncedAt time.Time
+}
+
+func newRevocationList() *revocationList {
+	return &revocationList{
+		tokens: map[string]time.Time{},
+		users:  map[string]Revocation{},
+	}
+}
+
+func (l *revocationList) add(revocations []Revocation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, revocation := range revocations {
+		if revocation.TokenID != "" {
+			l.tokens[revocation.TokenID] = revocation.ExpiresAt
+			continue
+		}
+		if existing, ok := l.users[revocation.UserID]; !ok || revocation.IssuedBefore.After(existing.IssuedBefore) {
+			l.users[revocation.UserID] = revocation
+		}
+	}
+}
+
+func (l *revocationList) revoked(claims *Claims) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if _, ok := l.tokens[claims.ID]; ok {
+		return true
+	}
+	revocation, ok := l.users[claims.Subject]
+	return ok && claims.IssuedAt != nil && claims.IssuedAt.Time.Before(revocation.IssuedBefore)
+}
+
+// prune forgets the revocations of tokens that have expired anyway.
+func (l *revocationList) prune(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for tokenID, expiresAt := range l.tokens {
+		if !expiresAt.IsZero() && expiresAt.Before(now) {
+			delete(l.tokens, tokenID)
+		}
+	}
+	for userID, revocation := range l.users {
+		if !revocation.ExpiresAt.IsZero() && revocation.ExpiresAt.Before(now) {
+			delete(l.users, userID)
+		}
+	}
+}
+
+func (l *revocationList) sync(ctx context.Context, source RevocationSource) error {
+	now := time.Now()
+	// the overlap makes up for clock skew between the gateway and the source.
+	revocations, err := source(ctx, l.lastSyncedAt.Add(-time.Minute))
+	if err != nil {
+		return err
+	}
+	l.add(revocations)
+	l.prune(now)
+	l.lastSyncedAt = now
+	return nil
+}
+
+func (l *revocationList) syncEvery(ctx context.Context, source RevocationSource, interval time.Duration, log *logrus.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.sync(ctx, source); err != nil {
+				// keep using the previous denylist until the next sync.
+				log.WithError(err).Error("unable to sync revoked tokens")
+			}
+		}
+	}
+}
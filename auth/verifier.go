@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrInsufficientClaims is returned for valid tokens that do not carry
+	// enough claims to build the user from.
+	ErrInsufficientClaims = errors.New("token does not carry enough claims")
+	// ErrUnknownKey is returned for tokens signed with a key the verifier does not know.
+	ErrUnknownKey = errors.New("token signed with an unknown key")
+	// ErrRevokedToken is returned for tokens that are on the denylist.
+	ErrRevokedToken = errors.New("token has been revoked")
+)
+
+// Config configures where the verifier gets its public keys from, when both
+// PublicKeyFile and JWKSURL are set the jwks document takes precedence.
+// Revoked tokens are read from Revocations every RevocationSyncInterval, a
+// token revoked elsewhere can be accepted until the next sync.
+type Config struct {
+	PublicKeyFile          string
+	JWKSURL                string
+	RefreshInterval        time.Duration
+	Issuer                 string
+	Revocations            RevocationSource
+	RevocationSyncInterval time.Duration
+}
+
+// Claims are the claims the user service puts in the tokens it issues.
+type Claims struct {
+	jwt.RegisteredClaims
+	Email         string   `json:"email"`
+	FullName      string   `json:"fullName"`
+	Country       string   `json:"country"`
+	Roles         []string `json:"roles"`
+	EmailVerified *bool    `json:"emailVerified"`
+	TotpEnabled   *bool    `json:"totpEnabled"`
+}
+
+// Complete reports whether the claims carry everything needed to build the user
+// and to check whether the token has been revoked.
+func (c *Claims) Complete() bool {
+	return c.ID != "" && c.IssuedAt != nil && c.Subject != "" && c.Email != "" && c.FullName != "" && c.Country != "" && c.Roles != nil &&
+		c.EmailVerified != nil && c.TotpEnabled != nil
+}
+
+// Verifier verifies jwt tokens locally against the configured public keys and
+// a local copy of the revoked tokens.
+type Verifier struct {
+	keys        *keySet
+	parser      *jwt.Parser
+	issuer      string
+	revocations *revocationList
+}
+
+// NewVerifier loads the configured public keys, when a jwks url is configured the
+// document is refreshed in the background until ctx is done.
+func NewVerifier(ctx context.Context, cfg Config, log *logrus.Logger) (*Verifier, error) {
+	v := &Verifier{
+		keys: &keySet{},
+		parser: jwt.NewParser(jwt.WithValidMethods([]string{
+			"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512",
+		})),
+		issuer:      cfg.Issuer,
+		revocations: newRevocationList(),
+	}
+	if cfg.Revocations != nil {
+		if err := v.revocations.sync(ctx, cfg.Revocations); err != nil {
+			return nil, err
+		}
+		if cfg.RevocationSyncInterval <= 0 {
+			cfg.RevocationSyncInterval = 30 * time.Second
+		}
+		go v.revocations.syncEvery(ctx, cfg.Revocations, cfg.RevocationSyncInterval, log)
+	}
+	if cfg.JWKSURL == "" {
+		key, err := loadPEMPublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys.set(map[string]crypto.PublicKey{"": key})
+		return v, nil
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	keys, err := fetchJWKS(ctx, client, cfg.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+	v.keys.set(keys)
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = 15 * time.Minute
+	}
+	go v.refreshJWKS(ctx, client, cfg, log)
+	return v, nil
+}
+
+func (v *Verifier) refreshJWKS(ctx context.Context, client *http.Client, cfg Config, log *logrus.Logger) {
+	ticker := time.NewTicker(cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			keys, err := fetchJWKS(ctx, client, cfg.JWKSURL)
+			if err != nil {
+				// keep using the previous keys until the next refresh.
+				log.WithError(err).WithField("jwksUrl", cfg.JWKSURL).Error("unable to refresh jwks")
+				continue
+			}
+			v.keys.set(keys)
+		}
+	}
+}
+
+// Verify validates the token's signature, expiry and issuer and returns its claims.
+// ErrInsufficientClaims and ErrUnknownKey are returned for tokens that can
+// not be verified locally but might still be valid.
+func (v *Verifier) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := v.keys.get(kid)
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		return key, nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrUnknownKey) {
+			return nil, ErrUnknownKey
+		}
+		return nil, err
+	}
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return nil, errors.New("token has an invalid issuer")
+	}
+	if !claims.Complete() {
+		return claims, ErrInsufficientClaims
+	}
+	if v.revocations.revoked(claims) {
+		return nil, ErrRevokedToken
+	}
+	return claims, nil
+}
+
+// Revoke rejects tokenString in subsequent calls to Verify until it expires,
+// the user service revokes it for the other gateway instances.
+func (v *Verifier) Revoke(tokenString string) {
+	claims := &Claims{}
+	if _, _, err := v.parser.ParseUnverified(tokenString, claims); err != nil || claims.ID == "" {
+		return
+	}
+	revocation := Revocation{TokenID: claims.ID}
+	if claims.ExpiresAt != nil {
+		revocation.ExpiresAt = claims.ExpiresAt.Time
+	}
+	v.revocations.add([]Revocation{revocation})
+}
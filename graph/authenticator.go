@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wisdommatt/ecommerce-microservice-public-api/auth"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+)
+
+var userCacheContextKey ContextKey = "user-cache-context-key"
+
+// Authenticator resolves the user a jwt token belongs to. Tokens are verified
+// locally when Verifier is set, the user service is only called when the
+// token can not be verified locally.
+type Authenticator struct {
+	UserService proto.UserServiceClient
+	Verifier    *auth.Verifier
+}
+
+// apiKeyGrant is the user an api key belongs to and the scopes it was granted.
+type apiKeyGrant struct {
+	id     string
+	user   *model.User
+	scopes []model.APIKeyScope
+}
+
+// userCache holds the users authenticated during a single http request.
+type userCache struct {
+	mu      sync.Mutex
+	users   map[string]*model.User
+	apiKeys map[string]*apiKeyGrant
+}
+
+// ContextWithUserCache makes authenticated users to be cached in ctx so that
+// a token is only authenticated once per request.
+func ContextWithUserCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, userCacheContextKey, &userCache{
+		users:   map[string]*model.User{},
+		apiKeys: map[string]*apiKeyGrant{},
+	})
+}
+
+// Authenticate returns the user jwtToken belongs to.
+func (a *Authenticator) Authenticate(ctx context.Context, jwtToken string) (*model.User, error) {
+	cache, ok := ctx.Value(userCacheContextKey).(*userCache)
+	if !ok {
+		return a.authenticate(ctx, jwtToken)
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if usr, ok := cache.users[jwtToken]; ok {
+		return usr, nil
+	}
+	usr, err := a.authenticate(ctx, jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	cache.users[jwtToken] = usr
+	return usr, nil
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, jwtToken string) (*model.User, error) {
+	if a.Verifier != nil {
+		claims, err := a.Verifier.Verify(jwtToken)
+		if err == nil {
+			return AuthClaimsToGqlUser(claims), nil
+		}
+		if !errors.Is(err, auth.ErrInsufficientClaims) && !errors.Is(err, auth.ErrUnknownKey) {
+			return nil, errors.New("you are not authenticated")
+		}
+	}
+	authUser, err := a.UserService.GetUserFromJWT(ctx, &proto.GetUserFromJWTInput{JwtToken: jwtToken})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return ProtoUserToGql(authUser.User), nil
+}
+
+// contextWithUser authenticates jwtToken and adds the user to ctx.
+func (a *Authenticator) contextWithUser(ctx context.Context, jwtToken string) (context.Context, error) {
+	usr, err := a.Authenticate(ctx, jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, userContextKey, usr), nil
+}
+
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, apiKey string) (*apiKeyGrant, error) {
+	cache, ok := ctx.Value(userCacheContextKey).(*userCache)
+	if ok {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		if grant, ok := cache.apiKeys[apiKey]; ok {
+			return grant, nil
+		}
+	}
+	response, err := a.UserService.GetUserFromApiKey(ctx, &proto.GetUserFromApiKeyInput{Key: apiKey})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	if response.GetApiKey().GetId() == "" || response.GetUser() == nil {
+		return nil, errors.New("you are not authenticated")
+	}
+	grant := &apiKeyGrant{
+		id:     response.ApiKey.Id,
+		user:   ProtoUserToGql(response.User),
+		scopes: ProtoAPIKeyToGql(response.ApiKey).Scopes,
+	}
+	if ok {
+		cache.apiKeys[apiKey] = grant
+	}
+	return grant, nil
+}
+
+// contextWithAPIKeyUser authenticates apiKey and adds the user and the key's
+// scopes to ctx.
+func (a *Authenticator) contextWithAPIKeyUser(ctx context.Context, apiKey string) (context.Context, error) {
+	grant, err := a.authenticateAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, userContextKey, grant.user)
+	return context.WithValue(ctx, apiKeyScopesContextKey, grant.scopes), nil
+}
+
+// Revoke makes the local verifier reject jwtToken right away, the other
+// instances reject it once they have synced the user service's denylist.
+func (a *Authenticator) Revoke(jwtToken string) {
+	if a.Verifier != nil {
+		a.Verifier.Revoke(jwtToken)
+	}
+}
+
+// RevokedTokens reads the denylist of locally verified tokens from the user service.
+func RevokedTokens(userService proto.UserServiceClient) auth.RevocationSource {
+	return func(ctx context.Context, since time.Time) ([]auth.Revocation, error) {
+		response, err := userService.GetRevokedTokens(ctx, &proto.GetRevokedTokensInput{Since: since.Unix()})
+		if err != nil {
+			return nil, parseGrpcError(err)
+		}
+		revocations := make([]auth.Revocation, len(response.GetRevokedTokens()))
+		for i, revokedToken := range response.GetRevokedTokens() {
+			revocations[i] = ProtoRevokedTokenToAuth(revokedToken)
+		}
+		return revocations, nil
+	}
+}
@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+var publicCacheContextKey ContextKey = "public-cache-context-key"
+
+// CacheControlMiddleware makes GET responses uncacheable, they are kept out of
+// shared caches when the request carries credentials. Anonymous automatic
+// persisted query requests can be made publicly cacheable by PublicCache.
+func CacheControlMiddleware(sessionCookieName string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(rw, r)
+				return
+			}
+			if hasCredentials(r, sessionCookieName) {
+				rw.Header().Set("Cache-Control", "private, no-store")
+				next.ServeHTTP(rw, r)
+				return
+			}
+			rw.Header().Set("Cache-Control", "no-store")
+			if isPersistedQueryRequest(r) {
+				r = r.WithContext(context.WithValue(r.Context(), publicCacheContextKey, true))
+			}
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func hasCredentials(r *http.Request, sessionCookieName string) bool {
+	if r.Header.Get("Authorization") != "" || r.Header.Get("X-API-Key") != "" {
+		return true
+	}
+	_, err := r.Cookie(sessionCookieName)
+	return err == nil
+}
+
+// isPersistedQueryRequest reports whether the request only sends the hash of an
+// automatic persisted query, its url then identifies the operation.
+func isPersistedQueryRequest(r *http.Request) bool {
+	query := r.URL.Query()
+	if query.Get("query") != "" {
+		return false
+	}
+	var extensions struct {
+		PersistedQuery struct {
+			Sha256Hash string `json:"sha256Hash"`
+		} `json:"persistedQuery"`
+	}
+	if err := json.Unmarshal([]byte(query.Get("extensions")), &extensions); err != nil {
+		return false
+	}
+	return extensions.PersistedQuery.Sha256Hash != ""
+}
+
+// PublicCache lets shared caches store the successful responses of anonymous
+// automatic persisted query GET requests for MaxAge.
+type PublicCache struct {
+	MaxAge time.Duration
+}
+
+var _ interface {
+	graphql.ResponseInterceptor
+	graphql.HandlerExtension
+} = PublicCache{}
+
+func (c PublicCache) ExtensionName() string {
+	return "PublicCache"
+}
+
+func (c PublicCache) Validate(schema graphql.ExecutableSchema) error {
+	if c.MaxAge <= 0 {
+		return fmt.Errorf("PublicCache max age must be positive")
+	}
+	return nil
+}
+
+func (c PublicCache) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	res := next(ctx)
+	if public, _ := ctx.Value(publicCacheContextKey).(bool); public && res != nil && len(res.Errors) == 0 {
+		writeHeader(ctx, "Cache-Control", fmt.Sprintf("public, max-age=%d", int(c.MaxAge.Seconds())))
+	}
+	return res
+}
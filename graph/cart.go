@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+)
+
+// buildCart computes the cart totals from the user's cart items, the products
+// are retrieved in a single batch through the product loader. Items whose
+// product no longer exists are returned as unavailable instead of failing the
+// whole cart.
+func (r *Resolver) buildCart(ctx context.Context, userCart *proto.GetUserCartResponse) (*model.Cart, error) {
+	cart := &model.Cart{
+		Items:            []*model.CartItem{},
+		UnavailableItems: []*model.UnavailableCartItem{},
+		Currency:         r.Currency,
+	}
+	if userCart.UpdatedAt > 0 {
+		updatedAt := time.Unix(userCart.UpdatedAt, 0).UTC()
+		cart.UpdatedAt = &updatedAt
+	}
+	skus := make([]string, len(userCart.GetItems()))
+	for i, item := range userCart.GetItems() {
+		skus[i] = item.ProductSku
+	}
+	products, errs := loadersFor(ctx).ProductBySku.LoadAll(ctx, skus)
+	var subtotal float64
+	for i, item := range userCart.GetItems() {
+		var notFoundErr *productNotFoundError
+		if errors.As(errs[i], &notFoundErr) {
+			cart.UnavailableItems = append(cart.UnavailableItems, ProtoCartItemToUnavailableGql(item))
+			continue
+		}
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		cartItem := ProtoCartItemToGql(item)
+		cartItem.Product = products[i]
+		cart.Items = append(cart.Items, cartItem)
+		cart.ItemCount += cartItem.Quantity
+		subtotal += products[i].Price * float64(cartItem.Quantity)
+	}
+	cart.Subtotal = math.Round(subtotal*100) / 100
+	return cart, nil
+}
+
+// clearUserCart removes every item from the user's cart.
+func (r *Resolver) clearUserCart(ctx context.Context, userId string) error {
+	userCart, err := r.CartServiceClient.GetUserCart(ctx, &proto.GetUserCartInput{UserId: userId})
+	if err != nil {
+		return parseGrpcError(err)
+	}
+	if len(userCart.GetItems()) == 0 {
+		return nil
+	}
+	itemIds := make([]string, len(userCart.GetItems()))
+	for i, item := range userCart.GetItems() {
+		itemIds[i] = item.Id
+	}
+	_, err = r.CartServiceClient.RemoveItemsFromCart(ctx, &proto.RemoveItemsFromCartInput{
+		UserId:  userId,
+		ItemIds: itemIds,
+	})
+	if err != nil {
+		return parseGrpcError(err)
+	}
+	return nil
+}
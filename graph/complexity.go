@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/generated"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
+)
+
+const (
+	// maxPageSize is the largest pagination limit the paginated queries accept.
+	maxPageSize = 100
+	// listSizeEstimate is the size assumed for lists that are not paginated,
+	// e.g the items in a cart.
+	listSizeEstimate = 20
+)
+
+// Complexity returns the functions used to compute the cost of an operation
+// before it is executed, paginated fields cost their limit times the cost of
+// a single edge and other lists cost listSizeEstimate times the cost of an item.
+func Complexity() generated.ComplexityRoot {
+	var c generated.ComplexityRoot
+	c.Query.Products = func(childComplexity int, filter *model.ProductFilter, sort *model.ProductSort, pagination model.Pagination) int {
+		return paginatedComplexity(childComplexity, pagination)
+	}
+	c.Query.GetUsers = func(childComplexity int, pagination model.Pagination) int {
+		return paginatedComplexity(childComplexity, pagination)
+	}
+	c.Query.GetUserCart = listComplexity
+	c.Query.APIKeys = listComplexity
+	c.Cart.Items = listComplexity
+	c.Order.Items = listComplexity
+	c.Mutation.RemoveItemsFromUserCart = func(childComplexity int, itemsID []string) int {
+		return 1 + len(itemsID)*childComplexity
+	}
+	return c
+}
+
+func paginatedComplexity(childComplexity int, pagination model.Pagination) int {
+	limit := pagination.Limit
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return 1 + limit*childComplexity
+}
+
+func listComplexity(childComplexity int) int {
+	return 1 + listSizeEstimate*childComplexity
+}
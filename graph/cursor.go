@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+const cursorPrefix = "cursor:"
+
+// encodeCursor wraps id in an opaque pagination cursor.
+func encodeCursor(id string) string {
+	return base64.URLEncoding.EncodeToString([]byte(cursorPrefix + id))
+}
+
+// decodeCursor returns the id wrapped in cursor, an empty cursor decodes to an empty id.
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	decoded, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return "", errors.New("invalid pagination cursor")
+	}
+	return strings.TrimPrefix(string(decoded), cursorPrefix), nil
+}
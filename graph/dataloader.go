@@ -0,0 +1,189 @@
+//go:generate go run github.com/vektah/dataloaden ProductLoader string *github.com/wisdommatt/ecommerce-microservice-public-api/graph/model.Product
+//go:generate go run github.com/vektah/dataloaden UserLoader string *github.com/wisdommatt/ecommerce-microservice-public-api/graph/model.User
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+)
+
+var loadersContextKey ContextKey = "dataloaders-context-key"
+
+// productNotFoundError is returned by the product loader for skus that do
+// not belong to any product.
+type productNotFoundError struct {
+	sku string
+}
+
+func (e *productNotFoundError) Error() string {
+	return fmt.Sprintf("product %s not found", e.sku)
+}
+
+// Loaders holds the data loaders for a single graphql operation.
+type Loaders struct {
+	ProductBySku *productLoader
+	UserByID     *userLoader
+}
+
+// Dataloaders installs a fresh set of loaders for every operation so that all the
+// lookups made while executing it are batched and cached together. Operations
+// sent over a websocket connection each get their own loaders too.
+type Dataloaders struct {
+	ProductService proto.ProductServiceClient
+	UserService    proto.UserServiceClient
+}
+
+var _ interface {
+	graphql.OperationInterceptor
+	graphql.HandlerExtension
+} = Dataloaders{}
+
+func (d Dataloaders) ExtensionName() string {
+	return "Dataloaders"
+}
+
+func (d Dataloaders) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d Dataloaders) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(contextWithLoaders(ctx, d.ProductService, d.UserService))
+}
+
+func contextWithLoaders(ctx context.Context, productService proto.ProductServiceClient, userService proto.UserServiceClient) context.Context {
+	return context.WithValue(ctx, loadersContextKey, &Loaders{
+		ProductBySku: newProductLoader(productService),
+		UserByID:     newUserLoader(userService),
+	})
+}
+
+// withLoaders returns a copy of ctx with a fresh set of loaders, it is used by
+// long lived operations like subscriptions that outlive a single batch.
+func (r *Resolver) withLoaders(ctx context.Context) context.Context {
+	return contextWithLoaders(ctx, r.ProductServiceClient, r.UserServiceClient)
+}
+
+// loadersFor returns the loaders installed in ctx by Dataloaders.
+func loadersFor(ctx context.Context) *Loaders {
+	return ctx.Value(loadersContextKey).(*Loaders)
+}
+
+// batchContext is the context a loader fetches its next batch with, it is the
+// context of the first load of the batch so that the request carries the
+// credentials, metadata and tracing span of the resolver that started it.
+type batchContext struct {
+	mu   sync.Mutex
+	ctx  context.Context
+	last context.Context
+}
+
+func (b *batchContext) bind(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ctx == nil {
+		b.ctx = ctx
+	}
+}
+
+// take returns the context of the batch being fetched, a batch whose context was
+// taken by the previous one reuses that context.
+func (b *batchContext) take() context.Context {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ctx != nil {
+		b.last, b.ctx = b.ctx, nil
+	}
+	if b.last == nil {
+		return context.Background()
+	}
+	return b.last
+}
+
+type productLoader struct {
+	loader *ProductLoader
+	batch  *batchContext
+}
+
+func (l *productLoader) Load(ctx context.Context, sku string) (*model.Product, error) {
+	l.batch.bind(ctx)
+	return l.loader.Load(sku)
+}
+
+func (l *productLoader) LoadAll(ctx context.Context, skus []string) ([]*model.Product, []error) {
+	l.batch.bind(ctx)
+	return l.loader.LoadAll(skus)
+}
+
+type userLoader struct {
+	loader *UserLoader
+	batch  *batchContext
+}
+
+func (l *userLoader) Load(ctx context.Context, id string) (*model.User, error) {
+	l.batch.bind(ctx)
+	return l.loader.Load(id)
+}
+
+func newProductLoader(productService proto.ProductServiceClient) *productLoader {
+	batch := &batchContext{}
+	loader := NewProductLoader(ProductLoaderConfig{
+		Wait:     2 * time.Millisecond,
+		MaxBatch: 100,
+		Fetch: func(skus []string) ([]*model.Product, []error) {
+			response, err := productService.GetProducts(batch.take(), &proto.GetProductsInput{Skus: skus})
+			if err != nil {
+				return nil, []error{parseGrpcError(err)}
+			}
+			productsBySku := make(map[string]*proto.Product, len(response.GetProducts()))
+			for _, product := range response.GetProducts() {
+				productsBySku[product.Sku] = product
+			}
+			products := make([]*model.Product, len(skus))
+			errs := make([]error, len(skus))
+			for i, sku := range skus {
+				product, ok := productsBySku[sku]
+				if !ok {
+					errs[i] = &productNotFoundError{sku: sku}
+					continue
+				}
+				products[i] = ProtoProductToGql(product)
+			}
+			return products, errs
+		},
+	})
+	return &productLoader{loader: loader, batch: batch}
+}
+
+// newUserLoader returns a loader that resolves users by id, ids that do not
+// belong to any user resolve to nil.
+func newUserLoader(userService proto.UserServiceClient) *userLoader {
+	batch := &batchContext{}
+	loader := NewUserLoader(UserLoaderConfig{
+		Wait:     2 * time.Millisecond,
+		MaxBatch: 100,
+		Fetch: func(ids []string) ([]*model.User, []error) {
+			response, err := userService.GetUsersByIds(batch.take(), &proto.GetUsersByIdsInput{Ids: ids})
+			if err != nil {
+				return nil, []error{parseGrpcError(err)}
+			}
+			usersByID := make(map[string]*proto.User, len(response.GetUsers()))
+			for _, user := range response.GetUsers() {
+				usersByID[user.Id] = user
+			}
+			users := make([]*model.User, len(ids))
+			for i, id := range ids {
+				if user, ok := usersByID[id]; ok {
+					users[i] = ProtoUserToGql(user)
+				}
+			}
+			return users, nil
+		},
+	})
+	return &userLoader{loader: loader, batch: batch}
+}
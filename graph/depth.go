@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// DepthLimit rejects operations that select fields nested deeper than MaxDepth,
+// introspection fields are not counted.
+type DepthLimit struct {
+	MaxDepth int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = DepthLimit{}
+
+func (d DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+	if d.MaxDepth < 1 {
+		return fmt.Errorf("DepthLimit max depth must be greater than zero")
+	}
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+	if op == nil {
+		return nil
+	}
+	depth := selectionSetDepth(op.SelectionSet, rc.Doc.Fragments)
+	if depth > d.MaxDepth {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.MaxDepth)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// selectionSetDepth returns the depth of the deepest field in selectionSet,
+// fragments are expanded in place. Fragment cycles are rejected while the
+// operation is validated.
+func selectionSetDepth(selectionSet ast.SelectionSet, fragments ast.FragmentDefinitionList) int {
+	var depth int
+	for _, selection := range selectionSet {
+		var selectionDepth int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			selectionDepth = 1 + selectionSetDepth(s.SelectionSet, fragments)
+		case *ast.InlineFragment:
+			selectionDepth = selectionSetDepth(s.SelectionSet, fragments)
+		case *ast.FragmentSpread:
+			if fragment := fragments.ForName(s.Name); fragment != nil {
+				selectionDepth = selectionSetDepth(fragment.SelectionSet, fragments)
+			}
+		}
+		if selectionDepth > depth {
+			depth = selectionDepth
+		}
+	}
+	return depth
+}
@@ -5,31 +5,151 @@ import (
 	"errors"
 
 	"github.com/99designs/gqlgen/graphql"
-	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
 )
 
 type ContextKey string
 
 var (
-	userContextKey ContextKey = "user-graphql-key"
-	JwtContextKey  ContextKey = "jwt-context-key"
+	userContextKey         ContextKey = "user-graphql-key"
+	apiKeyScopesContextKey ContextKey = "api-key-scopes-context-key"
+	JwtContextKey          ContextKey = "jwt-context-key"
+	ApiKeyContextKey       ContextKey = "api-key-context-key"
 )
 
 type DirectiveFunc func(ctx context.Context, obj interface{}, next graphql.Resolver) (res interface{}, err error)
 
-func IsAuthenticated(userService proto.UserServiceClient) DirectiveFunc {
-	return func(ctx context.Context, obj interface{}, next graphql.Resolver) (res interface{}, err error) {
-		jwtTokenI := ctx.Value(JwtContextKey)
-		if jwtTokenI == nil {
-			return nil, errors.New("you are not authenticated")
+// IsAuthenticated only allows authenticated users, requests authenticated with an
+// api key are only allowed when the key has been granted scope.
+func IsAuthenticated(authenticator *Authenticator) func(ctx context.Context, obj interface{}, next graphql.Resolver, scope *model.APIKeyScope) (res interface{}, err error) {
+	return func(ctx context.Context, obj interface{}, next graphql.Resolver, scope *model.APIKeyScope) (res interface{}, err error) {
+		ctx, err = authenticateRequest(ctx, authenticator)
+		if err != nil {
+			return nil, err
 		}
-		jwtToken := jwtTokenI.(string)
-		authUser, err := userService.GetUserFromJWT(ctx, &proto.GetUserFromJWTInput{JwtToken: jwtToken})
+		if err := checkAPIKeyScope(ctx, scope); err != nil {
+			return nil, err
+		}
+		return next(ctx)
+	}
+}
+
+// HasRole only allows authenticated users that have atleast one of the provided roles,
+// the user is authenticated here when @isAuthenticated has not been applied before it.
+func HasRole(authenticator *Authenticator) func(ctx context.Context, obj interface{}, next graphql.Resolver, roles []model.Role, scope *model.APIKeyScope) (res interface{}, err error) {
+	return func(ctx context.Context, obj interface{}, next graphql.Resolver, roles []model.Role, scope *model.APIKeyScope) (res interface{}, err error) {
+		ctx, authUser, err := authenticatedUser(ctx, authenticator)
 		if err != nil {
-			return nil, parseGrpcError(err)
+			return nil, err
+		}
+		if err := checkAPIKeyScope(ctx, scope); err != nil {
+			return nil, err
+		}
+		if !userHasRole(authUser, roles) {
+			return nil, errors.New("you are not allowed to perform this action")
 		}
-		usr := ProtoUserToGql(authUser.User)
-		ctx = context.WithValue(ctx, userContextKey, usr)
 		return next(ctx)
 	}
 }
+
+// RequiresVerifiedEmail only allows authenticated users that have verified their email address,
+// the user is authenticated here when @isAuthenticated has not been applied before it.
+// api key scopes are not checked here, it should be combined with @isAuthenticated or @hasRole.
+func RequiresVerifiedEmail(authenticator *Authenticator) DirectiveFunc {
+	return func(ctx context.Context, obj interface{}, next graphql.Resolver) (res interface{}, err error) {
+		ctx, authUser, err := authenticatedUser(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+		if !authUser.EmailVerified {
+			return nil, errors.New("you need to verify your email address to perform this action")
+		}
+		return next(ctx)
+	}
+}
+
+// authenticatedUser returns the user added to ctx by a previous directive,
+// authenticating the request when there is none.
+func authenticatedUser(ctx context.Context, authenticator *Authenticator) (context.Context, *model.User, error) {
+	if authUser, ok := ctx.Value(userContextKey).(*model.User); ok {
+		return ctx, authUser, nil
+	}
+	ctx, err := authenticateRequest(ctx, authenticator)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ctx, ctx.Value(userContextKey).(*model.User), nil
+}
+
+// currentUser looks the authenticated user up, the user in ctx is built from
+// the claims of a token that can be older than the user's last change.
+func currentUser(ctx context.Context) (*model.User, error) {
+	authUser := ctx.Value(userContextKey).(*model.User)
+	usr, err := loadersFor(ctx).UserByID.Load(ctx, authUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	if usr == nil {
+		return nil, errors.New("you are not authenticated")
+	}
+	return usr, nil
+}
+
+// authenticateRequest adds the user the request's jwt token or api key belongs
+// to to ctx, the jwt token is used when the request has both.
+func authenticateRequest(ctx context.Context, authenticator *Authenticator) (context.Context, error) {
+	if jwtToken, _ := ctx.Value(JwtContextKey).(string); jwtToken != "" {
+		return authenticator.contextWithUser(ctx, jwtToken)
+	}
+	if apiKey, _ := ctx.Value(ApiKeyContextKey).(string); apiKey != "" {
+		return authenticator.contextWithAPIKeyUser(ctx, apiKey)
+	}
+	return nil, errors.New("you are not authenticated")
+}
+
+// checkAPIKeyScope makes sure requests authenticated with an api key have been
+// granted scope, fields without a scope can not be accessed with api keys.
+func checkAPIKeyScope(ctx context.Context, scope *model.APIKeyScope) error {
+	scopes, ok := ctx.Value(apiKeyScopesContextKey).([]model.APIKeyScope)
+	if !ok {
+		return nil
+	}
+	if scope != nil {
+		for _, granted := range scopes {
+			if granted == *scope {
+				return nil
+			}
+		}
+	}
+	return errors.New("api key is not allowed to perform this action")
+}
+
+func userHasRole(user *model.User, roles []model.Role) bool {
+	for _, userRole := range user.Roles {
+		for _, role := range roles {
+			if userRole == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WebsocketInitFunc authenticates websocket connections using the jwt token
+// sent in the connection_init payload, since browsers cannot set headers on
+// websocket requests. The session cookie sent with the upgrade request is
+// used when the payload does not contain a token.
+func WebsocketInitFunc(authenticator *Authenticator) transport.WebsocketInitFunc {
+	return func(ctx context.Context, initPayload transport.InitPayload) (context.Context, error) {
+		jwtToken := ParseBearerToken(initPayload.Authorization())
+		if jwtToken == "" {
+			jwtToken, _ = ctx.Value(JwtContextKey).(string)
+		}
+		if jwtToken == "" {
+			return nil, errors.New("you are not authenticated")
+		}
+		ctx = context.WithValue(ctx, JwtContextKey, jwtToken)
+		return authenticator.contextWithUser(ctx, jwtToken)
+	}
+}
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/sirupsen/logrus"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/auth"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errInvalidCredentials is returned for every failed login so that the
+// response does not reveal whether an account exists for the email.
+var errInvalidCredentials = errors.New("invalid email or password")
+
+// isCredentialsError reports whether the user service rejected a login
+// because of the provided credentials.
+func isCredentialsError(err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound, codes.Unauthenticated, codes.InvalidArgument, codes.PermissionDenied:
+		return true
+	}
+	return false
+}
+
+func loginThrottledError(wait time.Duration) error {
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	err := gqlerror.Errorf("too many failed login attempts, try again in %d seconds", retryAfter)
+	err.Extensions = map[string]interface{}{
+		"code":       "LOGIN_THROTTLED",
+		"retryAfter": retryAfter,
+	}
+	return err
+}
+
+// reserveLogin reserves a login attempt for email from ip when logins are
+// guarded, the attempt is nil when they are not.
+func (r *Resolver) reserveLogin(span opentracing.Span, email, ip string) (*auth.LoginAttempt, error) {
+	if r.LoginGuard == nil {
+		return nil, nil
+	}
+	attempt, wait := r.LoginGuard.Reserve(email, ip)
+	return reservedLoginAttempt(span, attempt, wait)
+}
+
+// reserveSecondFactor reserves an attempt at the code of challengeID from ip
+// when logins are guarded, the attempt is nil when they are not.
+func (r *Resolver) reserveSecondFactor(span opentracing.Span, challengeID, ip string) (*auth.LoginAttempt, error) {
+	if r.LoginGuard == nil {
+		return nil, nil
+	}
+	attempt, wait := r.LoginGuard.ReserveSecondFactor(challengeID, ip)
+	return reservedLoginAttempt(span, attempt, wait)
+}
+
+func reservedLoginAttempt(span opentracing.Span, attempt *auth.LoginAttempt, wait time.Duration) (*auth.LoginAttempt, error) {
+	if attempt == nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.String("event", "login throttled"),
+			log.String("wait", wait.String()),
+		)
+		return nil, loginThrottledError(wait)
+	}
+	return attempt, nil
+}
+
+// loginSucceeded completes attempt with the user service's response, a login
+// that still has to pass a second factor has not succeeded yet.
+func loginSucceeded(attempt *auth.LoginAttempt, response *proto.LoginResponse) {
+	if attempt == nil {
+		return
+	}
+	if response.ChallengeId != "" {
+		attempt.Challenged(response.ChallengeId, unixTime(response.ChallengeExpiresAt))
+		return
+	}
+	attempt.Succeeded()
+}
+
+// loginFailed records attempt as a failed login, the lockouts it causes are
+// reported to the logger and the tracer.
+func (r *Resolver) loginFailed(ctx context.Context, attempt *auth.LoginAttempt) {
+	if attempt == nil {
+		return
+	}
+	span := opentracing.SpanFromContext(ctx)
+	for _, lockout := range attempt.Failed() {
+		if span != nil {
+			ext.Error.Set(span, true)
+			span.LogFields(
+				log.String("event", "login lockout"),
+				log.String("lockout.key", lockout.Key),
+				log.String("lockout.value", lockout.Value),
+				log.Int("lockout.failures", lockout.Failures),
+				log.String("lockout.until", lockout.Until.Format(time.RFC3339)),
+			)
+		}
+		if r.Log != nil {
+			r.Log.WithFields(logrus.Fields{
+				"key":      lockout.Key,
+				"value":    lockout.Value,
+				"failures": lockout.Failures,
+				"until":    lockout.Until,
+			}).Warn("login locked out after repeated failures")
+		}
+	}
+}
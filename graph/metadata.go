@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var requestMetadataContextKey ContextKey = "request-metadata-context-key"
+
+// requestMetadata is the information about the http request that is
+// forwarded to downstream services.
+type requestMetadata struct {
+	requestID string
+	clientIP  string
+	userAgent string
+}
+
+// RequestMetadataMiddleware records the request id, client ip and user agent so
+// that they can be forwarded with every downstream rpc.
+func RequestMetadataMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			clientIP = r.RemoteAddr
+		}
+		ctx := context.WithValue(r.Context(), requestMetadataContextKey, &requestMetadata{
+			requestID: middleware.GetReqID(r.Context()),
+			clientIP:  clientIP,
+			userAgent: r.UserAgent(),
+		})
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
+
+// clientIP returns the ip address of the client that sent the request.
+func clientIP(ctx context.Context) string {
+	if reqMetadata, ok := ctx.Value(requestMetadataContextKey).(*requestMetadata); ok {
+		return reqMetadata.clientIP
+	}
+	return ""
+}
+
+// UnaryClientMetadataInterceptor forwards the caller's identity and request
+// metadata to downstream services.
+func UnaryClientMetadataInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingMetadata(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientMetadataInterceptor is the streaming counterpart of UnaryClientMetadataInterceptor.
+func StreamClientMetadataInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingMetadata(ctx), desc, cc, method, opts...)
+	}
+}
+
+// withOutgoingMetadata forwards the credential the request was authenticated
+// with, the jwt token in authorization or else the api key in x-api-key.
+// Downstream services must verify that credential themselves, x-user-id and
+// the x-client-* headers are only informational and must not be trusted for
+// authorization.
+func withOutgoingMetadata(ctx context.Context) context.Context {
+	var kv []string
+	if jwtToken, _ := ctx.Value(JwtContextKey).(string); jwtToken != "" {
+		kv = append(kv, "authorization", jwtToken)
+	} else if apiKey, _ := ctx.Value(ApiKeyContextKey).(string); apiKey != "" {
+		kv = append(kv, "x-api-key", apiKey)
+	}
+	if authUser, ok := ctx.Value(userContextKey).(*model.User); ok {
+		kv = append(kv, "x-user-id", authUser.ID)
+	}
+	if reqMetadata, ok := ctx.Value(requestMetadataContextKey).(*requestMetadata); ok {
+		if reqMetadata.requestID != "" {
+			kv = append(kv, "x-request-id", reqMetadata.requestID)
+		}
+		kv = append(kv, "x-client-ip", reqMetadata.clientIP, "x-client-user-agent", reqMetadata.userAgent)
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
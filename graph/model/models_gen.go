@@ -2,6 +2,30 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type APIKey struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	LastUsedAt *time.Time    `json:"lastUsedAt"`
+}
+
+type Cart struct {
+	Items            []*CartItem            `json:"items"`
+	UnavailableItems []*UnavailableCartItem `json:"unavailableItems"`
+	ItemCount        int                    `json:"itemCount"`
+	Subtotal         float64                `json:"subtotal"`
+	Currency         string                 `json:"currency"`
+	UpdatedAt        *time.Time             `json:"updatedAt"`
+}
+
 type CartItem struct {
 	ID         string   `json:"id"`
 	ProductSku string   `json:"productSku"`
@@ -9,9 +33,22 @@ type CartItem struct {
 	Quantity   int      `json:"quantity"`
 }
 
+type CreatedAPIKey struct {
+	APIKey *APIKey `json:"apiKey"`
+	Key    string  `json:"key"`
+}
+
 type LoginResponse struct {
-	JwtToken string `json:"jwtToken"`
-	User     *User  `json:"user"`
+	JwtToken              *string                `json:"jwtToken"`
+	RefreshToken          *string                `json:"refreshToken"`
+	ExpiresAt             *time.Time             `json:"expiresAt"`
+	User                  *User                  `json:"user"`
+	SecondFactorChallenge *SecondFactorChallenge `json:"secondFactorChallenge"`
+}
+
+type Merchant struct {
+	ID       string `json:"id"`
+	FullName string `json:"fullName"`
 }
 
 type NewCartItem struct {
@@ -19,6 +56,10 @@ type NewCartItem struct {
 	Quantity   int    `json:"quantity"`
 }
 
+type NewOrder struct {
+	ShippingAddress string `json:"shippingAddress"`
+}
+
 type NewProduct struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
@@ -35,24 +76,248 @@ type NewUser struct {
 	Password string `json:"password"`
 }
 
+type Order struct {
+	ID              string       `json:"id"`
+	Items           []*OrderItem `json:"items"`
+	Subtotal        float64      `json:"subtotal"`
+	Currency        string       `json:"currency"`
+	ShippingAddress string       `json:"shippingAddress"`
+	Status          string       `json:"status"`
+	CreatedAt       time.Time    `json:"createdAt"`
+}
+
+type OrderItem struct {
+	ProductSku  string  `json:"productSku"`
+	ProductName string  `json:"productName"`
+	UnitPrice   float64 `json:"unitPrice"`
+	Quantity    int     `json:"quantity"`
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
 type Pagination struct {
-	AfterID *string `json:"afterId"`
-	Limit   int     `json:"limit"`
+	After *string `json:"after"`
+	Limit int     `json:"limit"`
 }
 
 type Product struct {
-	Sku         string  `json:"sku"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Category    string  `json:"category"`
-	Brand       string  `json:"brand"`
-	Price       float64 `json:"price"`
-	ImageURL    string  `json:"imageUrl"`
+	Sku         string    `json:"sku"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Brand       string    `json:"brand"`
+	Price       float64   `json:"price"`
+	ImageURL    string    `json:"imageUrl"`
+	MerchantID  string    `json:"merchantId"`
+	Merchant    *Merchant `json:"merchant"`
+}
+
+type ProductConnection struct {
+	Edges      []*ProductEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int            `json:"totalCount"`
+}
+
+type ProductEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Product `json:"node"`
+}
+
+type ProductFilter struct {
+	Category *string  `json:"category"`
+	Brand    *string  `json:"brand"`
+	MinPrice *float64 `json:"minPrice"`
+	MaxPrice *float64 `json:"maxPrice"`
+	Name     *string  `json:"name"`
+}
+
+type SecondFactorChallenge struct {
+	ChallengeID string    `json:"challengeId"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type TotpEnrollment struct {
+	ProvisioningURI string `json:"provisioningUri"`
+	Secret          string `json:"secret"`
+}
+
+type UnavailableCartItem struct {
+	ID         string `json:"id"`
+	ProductSku string `json:"productSku"`
+	Quantity   int    `json:"quantity"`
+}
+
+type UpdateProduct struct {
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	Category    *string  `json:"category"`
+	Brand       *string  `json:"brand"`
+	Price       *float64 `json:"price"`
+	ImageURL    *string  `json:"imageUrl"`
+}
+
+type UpdateProfile struct {
+	FullName *string `json:"fullName"`
+	Country  *string `json:"country"`
 }
 
 type User struct {
-	ID       string `json:"id"`
-	FullName string `json:"fullName"`
-	Email    string `json:"email"`
-	Country  string `json:"country"`
+	ID            string `json:"id"`
+	FullName      string `json:"fullName"`
+	Email         string `json:"email"`
+	Country       string `json:"country"`
+	Roles         []Role `json:"roles"`
+	EmailVerified bool   `json:"emailVerified"`
+	TotpEnabled   bool   `json:"totpEnabled"`
+}
+
+type UserConnection struct {
+	Edges      []*UserEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int         `json:"totalCount"`
+}
+
+type UserEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *User  `json:"node"`
+}
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeProductsWrite APIKeyScope = "PRODUCTS_WRITE"
+	APIKeyScopeCartRead      APIKeyScope = "CART_READ"
+	APIKeyScopeCartWrite     APIKeyScope = "CART_WRITE"
+	APIKeyScopeOrdersWrite   APIKeyScope = "ORDERS_WRITE"
+	APIKeyScopeProfileRead   APIKeyScope = "PROFILE_READ"
+)
+
+var AllAPIKeyScope = []APIKeyScope{
+	APIKeyScopeProductsWrite,
+	APIKeyScopeCartRead,
+	APIKeyScopeCartWrite,
+	APIKeyScopeOrdersWrite,
+	APIKeyScopeProfileRead,
+}
+
+func (e APIKeyScope) IsValid() bool {
+	switch e {
+	case APIKeyScopeProductsWrite, APIKeyScopeCartRead, APIKeyScopeCartWrite, APIKeyScopeOrdersWrite, APIKeyScopeProfileRead:
+		return true
+	}
+	return false
+}
+
+func (e APIKeyScope) String() string {
+	return string(e)
+}
+
+func (e *APIKeyScope) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = APIKeyScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ApiKeyScope", str)
+	}
+	return nil
+}
+
+func (e APIKeyScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ProductSort string
+
+const (
+	ProductSortNewest    ProductSort = "NEWEST"
+	ProductSortPriceAsc  ProductSort = "PRICE_ASC"
+	ProductSortPriceDesc ProductSort = "PRICE_DESC"
+	ProductSortNameAsc   ProductSort = "NAME_ASC"
+	ProductSortNameDesc  ProductSort = "NAME_DESC"
+)
+
+var AllProductSort = []ProductSort{
+	ProductSortNewest,
+	ProductSortPriceAsc,
+	ProductSortPriceDesc,
+	ProductSortNameAsc,
+	ProductSortNameDesc,
+}
+
+func (e ProductSort) IsValid() bool {
+	switch e {
+	case ProductSortNewest, ProductSortPriceAsc, ProductSortPriceDesc, ProductSortNameAsc, ProductSortNameDesc:
+		return true
+	}
+	return false
+}
+
+func (e ProductSort) String() string {
+	return string(e)
+}
+
+func (e *ProductSort) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductSort", str)
+	}
+	return nil
+}
+
+func (e ProductSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Role string
+
+const (
+	RoleCustomer Role = "CUSTOMER"
+	RoleMerchant Role = "MERCHANT"
+	RoleAdmin    Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleCustomer,
+	RoleMerchant,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleCustomer, RoleMerchant, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
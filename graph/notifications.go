@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/notification"
+)
+
+// sendEmail delivers email in the background, so that response times do not
+// reveal whether an email was sent.
+func (r *Resolver) sendEmail(ctx context.Context, email notification.Email) {
+	var opts []opentracing.StartSpanOption
+	if parentSpan := opentracing.SpanFromContext(ctx); parentSpan != nil {
+		opts = append(opts, opentracing.FollowsFrom(parentSpan.Context()))
+	}
+	span := r.Tracer.StartSpan("SendEmail", opts...)
+	go func() {
+		defer span.Finish()
+		ctx := opentracing.ContextWithSpan(context.Background(), span)
+		if err := r.Notifier.SendEmail(ctx, email); err != nil {
+			ext.Error.Set(span, true)
+			span.LogFields(
+				log.Error(err),
+			)
+		}
+	}()
+}
+
+// sendVerificationEmail requests a verification token for the user and emails it to them.
+func (r *Resolver) sendVerificationEmail(ctx context.Context, userId string) error {
+	response, err := r.UserServiceClient.RequestEmailVerification(ctx, &proto.RequestEmailVerificationInput{UserId: userId})
+	if err != nil {
+		return parseGrpcError(err)
+	}
+	r.sendEmail(ctx, notification.EmailVerificationEmail(
+		response.User.Email,
+		response.User.FullName,
+		linkWithToken(r.EmailVerificationURL, response.VerificationToken),
+	))
+	return nil
+}
+
+// linkWithToken adds token to the query string of the link in base.
+func linkWithToken(base, token string) string {
+	link, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errPersistedQueryNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+// PersistedQueryManifest maps the sha256 hash of the registered operations to
+// their query.
+type PersistedQueryManifest map[string]string
+
+// LoadPersistedQueryManifest reads a json object of sha256 hashes to queries
+// from filename, every hash is checked against its query.
+func LoadPersistedQueryManifest(filename string) (PersistedQueryManifest, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	manifest := PersistedQueryManifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid persisted query manifest: %w", err)
+	}
+	for hash, query := range manifest {
+		if queryHash(query) != hash {
+			return nil, fmt.Errorf("persisted query %s does not match its hash", hash)
+		}
+	}
+	return manifest, nil
+}
+
+// Cache returns an automatic persisted query cache that serves the manifest's
+// operations before looking them up in fallback, they can not be evicted by
+// the operations clients register there.
+func (m PersistedQueryManifest) Cache(fallback graphql.Cache) graphql.Cache {
+	return manifestCache{manifest: m, fallback: fallback}
+}
+
+type manifestCache struct {
+	manifest PersistedQueryManifest
+	fallback graphql.Cache
+}
+
+func (c manifestCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	if query, ok := c.manifest[key]; ok {
+		return query, true
+	}
+	return c.fallback.Get(ctx, key)
+}
+
+func (c manifestCache) Add(ctx context.Context, key string, value interface{}) {
+	if _, ok := c.manifest[key]; ok {
+		return
+	}
+	c.fallback.Add(ctx, key, value)
+}
+
+// PersistedQueryAllowlist only executes the operations in Manifest, clients can
+// either send the operation's hash as an automatic persisted query or send the
+// whole query. It replaces extension.AutomaticPersistedQuery, unknown hashes are
+// rejected instead of being registered.
+type PersistedQueryAllowlist struct {
+	Manifest PersistedQueryManifest
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = PersistedQueryAllowlist{}
+
+func (a PersistedQueryAllowlist) ExtensionName() string {
+	return "PersistedQueryAllowlist"
+}
+
+func (a PersistedQueryAllowlist) Validate(schema graphql.ExecutableSchema) error {
+	if len(a.Manifest) == 0 {
+		return fmt.Errorf("PersistedQueryAllowlist manifest can not be empty")
+	}
+	return nil
+}
+
+func (a PersistedQueryAllowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	if rawParams.Query != "" {
+		if _, ok := a.Manifest[queryHash(rawParams.Query)]; ok {
+			return nil
+		}
+		return persistedQueryNotAllowedError()
+	}
+	persistedQuery, _ := rawParams.Extensions["persistedQuery"].(map[string]interface{})
+	hash, _ := persistedQuery["sha256Hash"].(string)
+	query, ok := a.Manifest[hash]
+	if !ok {
+		return persistedQueryNotAllowedError()
+	}
+	rawParams.Query = query
+	return nil
+}
+
+func persistedQueryNotAllowedError() *gqlerror.Error {
+	err := gqlerror.Errorf("operation is not in the persisted query allowlist")
+	errcode.Set(err, errPersistedQueryNotAllowed)
+	return err
+}
+
+func queryHash(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:])
+}
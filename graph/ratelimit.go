@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/ratelimit"
+)
+
+const errRateLimited = "RATE_LIMITED"
+
+// RateLimit limits how often each client can select every root field, clients
+// are identified by their user id, api key or ip address in that order.
+// Every selection of a root field, including aliased ones, uses a unit of the
+// field's budget.
+type RateLimit struct {
+	Store         ratelimit.Store
+	Authenticator *Authenticator
+	// Default is the budget of the root fields that are not in Fields, those
+	// fields are not limited when it is zero.
+	Default ratelimit.Budget
+	Fields  map[string]ratelimit.Budget
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = RateLimit{}
+
+func (l RateLimit) ExtensionName() string {
+	return "RateLimit"
+}
+
+func (l RateLimit) Validate(schema graphql.ExecutableSchema) error {
+	if l.Store == nil || l.Authenticator == nil {
+		return fmt.Errorf("RateLimit store and authenticator can not be nil")
+	}
+	return nil
+}
+
+func (l RateLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+	if op == nil {
+		return nil
+	}
+	fields := map[string]int{}
+	countRootFields(op.SelectionSet, rc.Doc.Fragments, fields)
+	client := l.clientKey(ctx)
+
+	// the budgets are taken together so that a rejected operation does not use
+	// any of them, the fields are sorted so that the same field is reported.
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	var limited []string
+	var requests []ratelimit.Request
+	for _, field := range names {
+		budget, ok := l.Fields[field]
+		if !ok {
+			budget = l.Default
+		}
+		if budget.Limit == 0 {
+			continue
+		}
+		limited = append(limited, field)
+		requests = append(requests, ratelimit.Request{Key: client + ":" + field, N: fields[field], Budget: budget})
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+	results, err := l.Store.Take(ctx, requests...)
+	if err != nil {
+		// an unavailable store should not take the api down with it.
+		return nil
+	}
+	var headers *ratelimit.Result
+	for i, result := range results {
+		if !result.Allowed {
+			retryAfter := resetSeconds(result.Reset)
+			writeRateLimitHeaders(ctx, result)
+			writeHeader(ctx, "Retry-After", strconv.Itoa(retryAfter))
+			err := gqlerror.Errorf("rate limit exceeded for %s, retry in %d seconds", limited[i], retryAfter)
+			errcode.Set(err, errRateLimited)
+			return err
+		}
+		if headers == nil || result.Remaining < headers.Remaining {
+			headers = &results[i]
+		}
+	}
+	writeRateLimitHeaders(ctx, *headers)
+	return nil
+}
+
+// clientKey identifies the client that sent the request, requests whose
+// credentials are invalid are identified by their ip address.
+func (l RateLimit) clientKey(ctx context.Context) string {
+	if jwtToken, _ := ctx.Value(JwtContextKey).(string); jwtToken != "" {
+		if usr, err := l.Authenticator.Authenticate(ctx, jwtToken); err == nil {
+			return "user:" + usr.ID
+		}
+	} else if apiKey, _ := ctx.Value(ApiKeyContextKey).(string); apiKey != "" {
+		if grant, err := l.Authenticator.authenticateAPIKey(ctx, apiKey); err == nil {
+			return "apikey:" + grant.id
+		}
+	}
+	return "ip:" + clientIP(ctx)
+}
+
+func countRootFields(selectionSet ast.SelectionSet, fragments ast.FragmentDefinitionList, fields map[string]int) {
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name != "__typename" {
+				fields[s.Name]++
+			}
+		case *ast.InlineFragment:
+			countRootFields(s.SelectionSet, fragments, fields)
+		case *ast.FragmentSpread:
+			if fragment := fragments.ForName(s.Name); fragment != nil {
+				countRootFields(fragment.SelectionSet, fragments, fields)
+			}
+		}
+	}
+}
+
+func writeRateLimitHeaders(ctx context.Context, result ratelimit.Result) {
+	remaining := result.Remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+	writeHeader(ctx, "RateLimit-Limit", strconv.Itoa(result.Limit))
+	writeHeader(ctx, "RateLimit-Remaining", strconv.Itoa(remaining))
+	writeHeader(ctx, "RateLimit-Reset", strconv.Itoa(resetSeconds(result.Reset)))
+}
+
+func writeHeader(ctx context.Context, key, value string) {
+	rw, ok := ctx.Value(responseWriterContextKey).(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	rw.Header().Set(key, value)
+}
+
+func resetSeconds(reset time.Duration) int {
+	return int(math.Ceil(reset.Seconds()))
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/auth"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/notification"
 	"google.golang.org/grpc/status"
 )
 
@@ -18,6 +21,16 @@ type Resolver struct {
 	UserServiceClient    proto.UserServiceClient
 	ProductServiceClient proto.ProductServiceClient
 	CartServiceClient    proto.CartServiceClient
+	OrderServiceClient   proto.OrderServiceClient
+	Authenticator        *Authenticator
+	IdentityProviders    auth.Providers
+	LoginGuard           *auth.LoginGuard
+	SessionCookie        *SessionCookieConfig
+	Notifier             notification.Notifier
+	PasswordResetURL     string
+	EmailVerificationURL string
+	Currency             string
+	Log                  *logrus.Logger
 }
 
 func parseGrpcError(err error) error {
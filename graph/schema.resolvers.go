@@ -6,23 +6,30 @@ package graph
 import (
 	"context"
 	"errors"
+	"fmt"
 	"html"
+	"strings"
 
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/opentracing/opentracing-go/log"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/auth"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/generated"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
-	"google.golang.org/grpc/metadata"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/notification"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (r *cartItemResolver) Product(ctx context.Context, obj *model.CartItem) (*model.Product, error) {
-	// TODO(wisdommatt): use dataloader to retrieve products
-	return r.Query().GetProduct(ctx, obj.ProductSku)
+	if obj.Product != nil {
+		return obj.Product, nil
+	}
+	return loadersFor(ctx).ProductBySku.Load(ctx, obj.ProductSku)
 }
 
-func (r *mutationResolver) AuthLogin(ctx context.Context, email string, password string) (*model.LoginResponse, error) {
+func (r *mutationResolver) AuthLogin(ctx context.Context, email string, password string, setCookie *bool) (*model.LoginResponse, error) {
 	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "AuthLogin")
 	defer span.Finish()
 	span.SetTag("param.email", email)
@@ -37,11 +44,151 @@ func (r *mutationResolver) AuthLogin(ctx context.Context, email string, password
 		)
 		return nil, errors.New("all fields are required")
 	}
+	attempt, err := r.reserveLogin(span, email, clientIP(ctx))
+	if err != nil {
+		return nil, err
+	}
 	authResponse, err := r.UserServiceClient.LoginUser(ctx, &proto.LoginInput{Email: email, Password: password})
 	if err != nil {
+		if !isCredentialsError(err) {
+			if attempt != nil {
+				attempt.Cancel()
+			}
+			return nil, parseGrpcError(err)
+		}
+		r.loginFailed(ctx, attempt)
+		return nil, errInvalidCredentials
+	}
+	loginSucceeded(attempt, authResponse)
+	return r.loginResponse(ctx, authResponse, unpointBool(setCookie)), nil
+}
+
+func (r *mutationResolver) AuthWithProvider(ctx context.Context, provider string, code string, redirectURI string, setCookie *bool) (*model.LoginResponse, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "AuthWithProvider")
+	defer span.Finish()
+	span.SetTag("param.provider", provider)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if provider == "" || code == "" || redirectURI == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("all fields are required")),
+		)
+		return nil, errors.New("all fields are required")
+	}
+	identityProvider, err := r.IdentityProviders.Get(provider)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(err),
+		)
+		return nil, err
+	}
+	identity, err := identityProvider.Exchange(ctx, code, redirectURI)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(err),
+		)
+		// the provider's error details are only useful in the traces.
+		if errors.Is(err, auth.ErrProviderExchange) {
+			return nil, auth.ErrProviderExchange
+		}
+		return nil, err
+	}
+	authResponse, err := r.UserServiceClient.LoginWithProvider(ctx, &proto.ProviderLoginInput{
+		Provider:      strings.ToLower(provider),
+		Subject:       identity.Subject,
+		Email:         strings.ToLower(identity.Email),
+		FullName:      html.EscapeString(identity.FullName),
+		EmailVerified: identity.EmailVerified,
+	})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return r.loginResponse(ctx, authResponse, unpointBool(setCookie)), nil
+}
+
+func (r *mutationResolver) AuthVerifySecondFactor(ctx context.Context, challengeID string, code string, setCookie *bool) (*model.LoginResponse, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "AuthVerifySecondFactor")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if challengeID == "" || code == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("all fields are required")),
+		)
+		return nil, errors.New("all fields are required")
+	}
+	attempt, err := r.reserveSecondFactor(span, challengeID, clientIP(ctx))
+	if err != nil {
+		return nil, err
+	}
+	authResponse, err := r.UserServiceClient.VerifySecondFactor(ctx, &proto.VerifySecondFactorInput{
+		ChallengeId: challengeID,
+		Code:        strings.TrimSpace(code),
+	})
+	if err != nil {
+		if !isCredentialsError(err) {
+			if attempt != nil {
+				attempt.Cancel()
+			}
+			return nil, parseGrpcError(err)
+		}
+		r.loginFailed(ctx, attempt)
 		return nil, parseGrpcError(err)
 	}
-	return ProtoLoginResponseToGql(authResponse), nil
+	loginSucceeded(attempt, authResponse)
+	return r.loginResponse(ctx, authResponse, unpointBool(setCookie)), nil
+}
+
+func (r *mutationResolver) AuthRefresh(ctx context.Context, refreshToken *string, setCookie *bool) (*model.LoginResponse, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "AuthRefresh")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	token, useCookie := unpointStr(refreshToken), unpointBool(setCookie)
+	if token == "" {
+		// browser clients send the refresh token as a cookie, the new tokens
+		// are then set as cookies too.
+		token, _ = ctx.Value(RefreshTokenContextKey).(string)
+		useCookie = true
+	}
+	if token == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("refresh token is required")),
+		)
+		return nil, errors.New("refresh token is required")
+	}
+	authResponse, err := r.UserServiceClient.RefreshToken(ctx, &proto.RefreshTokenInput{RefreshToken: token})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return r.loginResponse(ctx, authResponse, useCookie), nil
+}
+
+func (r *mutationResolver) AuthLogout(ctx context.Context, refreshToken *string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "AuthLogout")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	jwtToken := ctx.Value(JwtContextKey).(string)
+	token := unpointStr(refreshToken)
+	if token == "" {
+		token, _ = ctx.Value(RefreshTokenContextKey).(string)
+	}
+	response, err := r.UserServiceClient.LogoutUser(ctx, &proto.LogoutInput{
+		JwtToken:     jwtToken,
+		RefreshToken: token,
+	})
+	if err != nil {
+		return false, parseGrpcError(err)
+	}
+	r.Authenticator.Revoke(jwtToken)
+	r.clearSessionCookie(ctx)
+	return response.Success, nil
 }
 
 func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser) (*model.User, error) {
@@ -63,9 +210,274 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser)
 	if err != nil {
 		return nil, parseGrpcError(err)
 	}
+	// the user can request another verification email, so failing to send
+	// this one should not fail the signup.
+	if err := r.sendVerificationEmail(ctx, newUser.Id); err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.String("event", "unable to send verification email"),
+			log.Error(err),
+		)
+	}
 	return ProtoUserToGql(newUser), nil
 }
 
+func (r *mutationResolver) RequestPasswordReset(ctx context.Context, email string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "RequestPasswordReset")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("email is required")),
+		)
+		return false, errors.New("email is required")
+	}
+	resetResponse, err := r.UserServiceClient.RequestPasswordReset(ctx, &proto.RequestPasswordResetInput{Email: html.EscapeString(email)})
+	if err != nil {
+		// the response must not reveal whether an account exists for the email.
+		if status.Code(err) == codes.NotFound {
+			return true, nil
+		}
+		return false, parseGrpcError(err)
+	}
+	r.sendEmail(ctx, notification.PasswordResetEmail(
+		resetResponse.User.Email,
+		resetResponse.User.FullName,
+		linkWithToken(r.PasswordResetURL, resetResponse.ResetToken),
+	))
+	return true, nil
+}
+
+func (r *mutationResolver) ResetPassword(ctx context.Context, token string, newPassword string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "ResetPassword")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if token == "" || newPassword == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("all fields are required")),
+		)
+		return false, errors.New("all fields are required")
+	}
+	response, err := r.UserServiceClient.ResetPassword(ctx, &proto.ResetPasswordInput{
+		ResetToken:  token,
+		NewPassword: newPassword,
+	})
+	if err != nil {
+		return false, parseGrpcError(err)
+	}
+	return response.Success, nil
+}
+
+func (r *mutationResolver) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "VerifyEmail")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if token == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("token is required")),
+		)
+		return nil, errors.New("token is required")
+	}
+	user, err := r.UserServiceClient.VerifyEmail(ctx, &proto.VerifyEmailInput{VerificationToken: token})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return ProtoUserToGql(user), nil
+}
+
+func (r *mutationResolver) ResendVerificationEmail(ctx context.Context) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "ResendVerificationEmail")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	usr, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	if usr.EmailVerified {
+		return true, nil
+	}
+	if err := r.sendVerificationEmail(ctx, usr.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) UpdateProfile(ctx context.Context, input model.UpdateProfile) (*model.User, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "UpdateProfile")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if input.FullName == nil && input.Country == nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("atleast one field must be provided")),
+		)
+		return nil, errors.New("atleast one field must be provided")
+	}
+	if isEmptyStr(input.FullName) || isEmptyStr(input.Country) {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("fields cannot be empty")),
+		)
+		return nil, errors.New("fields cannot be empty")
+	}
+	span.LogFields(
+		log.Object("param.input", input),
+	)
+	authUser := ctx.Value(userContextKey).(*model.User)
+	user, err := r.UserServiceClient.UpdateProfile(ctx, GqlUpdateProfileToProto(authUser.ID, &input))
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return ProtoUserToGql(user), nil
+}
+
+func (r *mutationResolver) ChangePassword(ctx context.Context, currentPassword string, newPassword string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "ChangePassword")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if currentPassword == "" || newPassword == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("all fields are required")),
+		)
+		return false, errors.New("all fields are required")
+	}
+	if currentPassword == newPassword {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("new password must be different from the current password")),
+		)
+		return false, errors.New("new password must be different from the current password")
+	}
+	authUser := ctx.Value(userContextKey).(*model.User)
+	response, err := r.UserServiceClient.ChangePassword(ctx, &proto.ChangePasswordInput{
+		UserId:          authUser.ID,
+		CurrentPassword: currentPassword,
+		NewPassword:     newPassword,
+	})
+	if err != nil {
+		return false, parseGrpcError(err)
+	}
+	return response.Success, nil
+}
+
+func (r *mutationResolver) DeleteAccount(ctx context.Context, password string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "DeleteAccount")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if password == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("password is required")),
+		)
+		return false, errors.New("password is required")
+	}
+	authUser := ctx.Value(userContextKey).(*model.User)
+	response, err := r.UserServiceClient.DeleteUser(ctx, &proto.DeleteUserInput{
+		UserId:   authUser.ID,
+		Password: password,
+	})
+	if err != nil {
+		return false, parseGrpcError(err)
+	}
+	if err := r.clearUserCart(ctx, authUser.ID); err != nil {
+		// the account is already gone, a leftover cart should not fail the deletion.
+		span.LogFields(
+			log.String("event", "unable to clear cart after deleting account"),
+			log.Error(err),
+		)
+	}
+	r.Authenticator.Revoke(ctx.Value(JwtContextKey).(string))
+	r.clearSessionCookie(ctx)
+	return response.Success, nil
+}
+
+func (r *mutationResolver) EnrollTotp(ctx context.Context) (*model.TotpEnrollment, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "EnrollTotp")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	// the claims of the token can be older than the user enabling it.
+	usr, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if usr.TotpEnabled {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("two-factor authentication is already enabled")),
+		)
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	enrollment, err := r.UserServiceClient.EnrollTotp(ctx, &proto.EnrollTotpInput{UserId: usr.ID})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return &model.TotpEnrollment{
+		ProvisioningURI: enrollment.ProvisioningUri,
+		Secret:          enrollment.Secret,
+	}, nil
+}
+
+func (r *mutationResolver) ConfirmTotp(ctx context.Context, code string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "ConfirmTotp")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("code is required")),
+		)
+		return false, errors.New("code is required")
+	}
+	authUser := ctx.Value(userContextKey).(*model.User)
+	response, err := r.UserServiceClient.ConfirmTotp(ctx, &proto.TotpCodeInput{
+		UserId: authUser.ID,
+		Code:   code,
+	})
+	if err != nil {
+		return false, parseGrpcError(err)
+	}
+	return response.Success, nil
+}
+
+func (r *mutationResolver) DisableTotp(ctx context.Context, code string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "DisableTotp")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("code is required")),
+		)
+		return false, errors.New("code is required")
+	}
+	authUser := ctx.Value(userContextKey).(*model.User)
+	response, err := r.UserServiceClient.DisableTotp(ctx, &proto.TotpCodeInput{
+		UserId: authUser.ID,
+		Code:   code,
+	})
+	if err != nil {
+		return false, parseGrpcError(err)
+	}
+	return response.Success, nil
+}
+
 func (r *mutationResolver) AddNewProduct(ctx context.Context, input model.NewProduct) (*model.Product, error) {
 	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "AddNewProduct")
 	defer span.Finish()
@@ -81,10 +493,6 @@ func (r *mutationResolver) AddNewProduct(ctx context.Context, input model.NewPro
 	span.LogFields(
 		log.Object("param.input", input),
 	)
-	metaData := metadata.New(map[string]string{
-		"Authorization": ctx.Value(JwtContextKey).(string),
-	})
-	ctx = metadata.NewOutgoingContext(ctx, metaData)
 	newProduct, err := r.ProductServiceClient.AddProduct(ctx, GqlNewProductToProto(&input))
 	if err != nil {
 		return nil, parseGrpcError(err)
@@ -92,6 +500,54 @@ func (r *mutationResolver) AddNewProduct(ctx context.Context, input model.NewPro
 	return ProtoProductToGql(newProduct), nil
 }
 
+func (r *mutationResolver) UpdateProduct(ctx context.Context, sku string, input model.UpdateProduct) (*model.Product, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "UpdateProduct")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if sku == "" {
+		return nil, errors.New("sku cannot be empty")
+	}
+	if isEmptyStr(input.Name) || isEmptyStr(input.Category) || isEmptyStr(input.Description) || isEmptyStr(input.ImageURL) || (input.Price != nil && *input.Price <= 0) {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("only brand can be set to an empty value")),
+		)
+		return nil, errors.New("only brand can be set to an empty value")
+	}
+	span.LogFields(
+		log.String("param.sku", sku),
+		log.Object("param.input", input),
+	)
+	// the product service checks the merchant and applies the update atomically.
+	authUser := ctx.Value(userContextKey).(*model.User)
+	updatedProduct, err := r.ProductServiceClient.UpdateProduct(ctx, GqlUpdateProductToProto(sku, authUser.ID, &input))
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return ProtoProductToGql(updatedProduct), nil
+}
+
+func (r *mutationResolver) DeleteProduct(ctx context.Context, sku string) (*model.Product, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "DeleteProduct")
+	defer span.Finish()
+	span.SetTag("param.sku", sku)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if sku == "" {
+		return nil, errors.New("sku cannot be empty")
+	}
+	authUser := ctx.Value(userContextKey).(*model.User)
+	deletedProduct, err := r.ProductServiceClient.DeleteProduct(ctx, &proto.DeleteProductInput{
+		Sku:        sku,
+		MerchantId: authUser.ID,
+	})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return ProtoProductToGql(deletedProduct), nil
+}
+
 func (r *mutationResolver) AddToCart(ctx context.Context, input model.NewCartItem) (*model.CartItem, error) {
 	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "AddToCart")
 	defer span.Finish()
@@ -104,14 +560,67 @@ func (r *mutationResolver) AddToCart(ctx context.Context, input model.NewCartIte
 		)
 		return nil, errors.New("all fields are required")
 	}
+	if input.Quantity < 0 {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("quantity cannot be negative")),
+		)
+		return nil, errors.New("quantity cannot be negative")
+	}
 	authUser := ctx.Value(userContextKey).(*model.User)
-	newCartItem, err := r.CartServiceClient.AddToCart(ctx, GqlNewCartItemToProto(&input, authUser.ID))
+	// the cart service merges the item into the existing one for the product,
+	// concurrent requests can not create duplicate items.
+	newItem := GqlNewCartItemToProto(&input, authUser.ID)
+	newCartItem, err := r.CartServiceClient.AddToCart(ctx, newItem)
 	if err != nil {
 		return nil, parseGrpcError(err)
 	}
 	return ProtoCartItemToGql(newCartItem), nil
 }
 
+func (r *mutationResolver) UpdateCartItemQuantity(ctx context.Context, itemID string, quantity int) (*model.CartItem, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "UpdateCartItemQuantity")
+	defer span.Finish()
+	span.SetTag("param.itemId", itemID)
+	span.SetTag("param.quantity", quantity)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if itemID == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("item id is required")),
+		)
+		return nil, errors.New("item id is required")
+	}
+	if quantity < 0 {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("quantity cannot be negative")),
+		)
+		return nil, errors.New("quantity cannot be negative")
+	}
+	authUser := ctx.Value(userContextKey).(*model.User)
+	if quantity == 0 {
+		_, err := r.CartServiceClient.RemoveItemsFromCart(ctx, &proto.RemoveItemsFromCartInput{
+			UserId:  authUser.ID,
+			ItemIds: []string{itemID},
+		})
+		if err != nil {
+			return nil, parseGrpcError(err)
+		}
+		return nil, nil
+	}
+	updatedCartItem, err := r.CartServiceClient.UpdateCartItem(ctx, &proto.UpdateCartItemInput{
+		UserId:   authUser.ID,
+		ItemId:   itemID,
+		Quantity: int32(quantity),
+	})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return ProtoCartItemToGql(updatedCartItem), nil
+}
+
 func (r *mutationResolver) RemoveItemsFromUserCart(ctx context.Context, itemsID []string) ([]*model.CartItem, error) {
 	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "RemoveItemsFromUserCart")
 	defer span.Finish()
@@ -139,6 +648,136 @@ func (r *mutationResolver) RemoveItemsFromUserCart(ctx context.Context, itemsID
 	return cartItems, nil
 }
 
+func (r *mutationResolver) PlaceOrder(ctx context.Context, input model.NewOrder) (*model.Order, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "PlaceOrder")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if strings.TrimSpace(input.ShippingAddress) == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("shipping address is required")),
+		)
+		return nil, errors.New("shipping address is required")
+	}
+	span.LogFields(
+		log.Object("param.input", input),
+	)
+	authUser := ctx.Value(userContextKey).(*model.User)
+	userCart, err := r.CartServiceClient.GetUserCart(ctx, &proto.GetUserCartInput{UserId: authUser.ID})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	if len(userCart.GetItems()) == 0 {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("cart is empty")),
+		)
+		return nil, errors.New("your cart is empty")
+	}
+	cart, err := r.buildCart(ctx, userCart)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(err),
+		)
+		return nil, err
+	}
+	if len(cart.UnavailableItems) > 0 {
+		sku := cart.UnavailableItems[0].ProductSku
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(&productNotFoundError{sku: sku}),
+		)
+		return nil, fmt.Errorf("product %s in your cart is no longer available, remove it and try again", sku)
+	}
+	order, err := r.OrderServiceClient.PlaceOrder(ctx, GqlCartToProtoNewOrder(cart, authUser.ID, input.ShippingAddress))
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	itemIds := make([]string, len(cart.Items))
+	for i, item := range cart.Items {
+		itemIds[i] = item.ID
+	}
+	_, err = r.CartServiceClient.RemoveItemsFromCart(ctx, &proto.RemoveItemsFromCartInput{
+		UserId:  authUser.ID,
+		ItemIds: itemIds,
+	})
+	if err != nil {
+		// the order has already been placed, failing to clear the cart
+		// should not fail the checkout.
+		span.LogFields(
+			log.String("event", "unable to clear cart after placing order"),
+			log.Error(err),
+		)
+	}
+	return ProtoOrderToGql(order), nil
+}
+
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, name string, scopes []model.APIKeyScope, password string) (*model.CreatedAPIKey, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "CreateAPIKey")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(scopes) == 0 {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("name and atleast one scope must be provided")),
+		)
+		return nil, errors.New("name and atleast one scope must be provided")
+	}
+	if password == "" {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("password is required")),
+		)
+		return nil, errors.New("password is required")
+	}
+	authUser := ctx.Value(userContextKey).(*model.User)
+	response, err := r.UserServiceClient.CreateApiKey(ctx, &proto.CreateApiKeyInput{
+		UserId:   authUser.ID,
+		Name:     html.EscapeString(name),
+		Scopes:   GqlAPIKeyScopesToProto(scopes),
+		Password: password,
+	})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return &model.CreatedAPIKey{
+		APIKey: ProtoAPIKeyToGql(response.ApiKey),
+		Key:    response.Key,
+	}, nil
+}
+
+func (r *mutationResolver) RevokeAPIKey(ctx context.Context, id string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "RevokeAPIKey")
+	defer span.Finish()
+	span.SetTag("param.id", id)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	authUser := ctx.Value(userContextKey).(*model.User)
+	response, err := r.UserServiceClient.RevokeApiKey(ctx, &proto.RevokeApiKeyInput{
+		UserId: authUser.ID,
+		Id:     id,
+	})
+	if err != nil {
+		return false, parseGrpcError(err)
+	}
+	return response.Success, nil
+}
+
+func (r *productResolver) Merchant(ctx context.Context, obj *model.Product) (*model.Merchant, error) {
+	if obj.MerchantID == "" {
+		return nil, nil
+	}
+	merchant, err := loadersFor(ctx).UserByID.Load(ctx, obj.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	return GqlUserToMerchant(merchant), nil
+}
+
 func (r *queryResolver) GetProduct(ctx context.Context, sku string) (*model.Product, error) {
 	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "GetProduct")
 	defer span.Finish()
@@ -154,30 +793,84 @@ func (r *queryResolver) GetProduct(ctx context.Context, sku string) (*model.Prod
 	return ProtoProductToGql(product), nil
 }
 
-func (r *queryResolver) GetUsers(ctx context.Context, pagination model.Pagination) ([]*model.User, error) {
+func (r *queryResolver) Products(ctx context.Context, filter *model.ProductFilter, sort *model.ProductSort, pagination model.Pagination) (*model.ProductConnection, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "Products")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	if pagination.Limit > maxPageSize {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("pagination max of 100 exceeded")),
+		)
+		return nil, errors.New("pagination max is 100")
+	}
+	if filter != nil && ((filter.MinPrice != nil && *filter.MinPrice < 0) || (filter.MaxPrice != nil && *filter.MaxPrice < 0)) {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("price bounds cannot be negative")),
+		)
+		return nil, errors.New("price bounds cannot be negative")
+	}
+	if filter != nil && filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(errors.New("minPrice cannot be greater than maxPrice")),
+		)
+		return nil, errors.New("minPrice cannot be greater than maxPrice")
+	}
+	afterSku, err := decodeCursor(unpointStr(pagination.After))
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(err),
+		)
+		return nil, err
+	}
+	span.LogFields(
+		log.Object("param.filter", filter),
+		log.Object("param.sort", sort),
+	)
+	productsResponse, err := r.ProductServiceClient.ListProducts(ctx, &proto.ListProductsInput{
+		Filter:   GqlProductFilterToProto(filter),
+		Sort:     GqlProductSortToProto(sort),
+		AfterSku: afterSku,
+		Limit:    int32(pagination.Limit),
+	})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	return ProtoProductsResponseToGqlConnection(productsResponse), nil
+}
+
+func (r *queryResolver) GetUsers(ctx context.Context, pagination model.Pagination) (*model.UserConnection, error) {
 	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "GetUsers")
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	if pagination.Limit > 100 {
+	if pagination.Limit > maxPageSize {
 		ext.Error.Set(span, true)
 		span.LogFields(
 			log.Error(errors.New("pagination max of 100 exceeded")),
 		)
 		return nil, errors.New("pagination max is 100")
 	}
+	afterID, err := decodeCursor(unpointStr(pagination.After))
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(err),
+		)
+		return nil, err
+	}
 	usersResponse, err := r.UserServiceClient.GetUsers(ctx, &proto.GetUsersFilter{
-		AfterId: unpointStr(pagination.AfterID),
+		AfterId: afterID,
 		Limit:   int32(pagination.Limit),
 	})
 	if err != nil {
 		return nil, parseGrpcError(err)
 	}
-	var users []*model.User
-	for _, user := range usersResponse.GetUsers() {
-		users = append(users, ProtoUserToGql(user))
-	}
-	return users, nil
+	return ProtoUsersResponseToGqlConnection(usersResponse), nil
 }
 
 func (r *queryResolver) GetUserCart(ctx context.Context) ([]*model.CartItem, error) {
@@ -197,17 +890,101 @@ func (r *queryResolver) GetUserCart(ctx context.Context) ([]*model.CartItem, err
 	return cartItems, nil
 }
 
+func (r *queryResolver) Cart(ctx context.Context) (*model.Cart, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "Cart")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	authUser := ctx.Value(userContextKey).(*model.User)
+	userCart, err := r.CartServiceClient.GetUserCart(ctx, &proto.GetUserCartInput{UserId: authUser.ID})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	cart, err := r.buildCart(ctx, userCart)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(
+			log.Error(err),
+		)
+		return nil, err
+	}
+	return cart, nil
+}
+
 func (r *queryResolver) GetUser(ctx context.Context) (*model.User, error) {
 	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "GetUser")
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	jwtToken := ctx.Value(JwtContextKey).(string)
-	userResponse, err := r.UserServiceClient.GetUserFromJWT(ctx, &proto.GetUserFromJWTInput{JwtToken: jwtToken})
+	// the user in ctx can be built from the claims of an older token, the
+	// current user is looked up instead.
+	authUser := ctx.Value(userContextKey).(*model.User)
+	usr, err := loadersFor(ctx).UserByID.Load(ctx, authUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	if usr == nil {
+		return nil, errors.New("user not found")
+	}
+	return usr, nil
+}
+
+func (r *queryResolver) APIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "APIKeys")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	authUser := ctx.Value(userContextKey).(*model.User)
+	response, err := r.UserServiceClient.GetApiKeys(ctx, &proto.GetApiKeysInput{UserId: authUser.ID})
+	if err != nil {
+		return nil, parseGrpcError(err)
+	}
+	apiKeys := []*model.APIKey{}
+	for _, apiKey := range response.GetApiKeys() {
+		apiKeys = append(apiKeys, ProtoAPIKeyToGql(apiKey))
+	}
+	return apiKeys, nil
+}
+
+func (r *subscriptionResolver) CartUpdated(ctx context.Context) (<-chan *model.Cart, error) {
+	authUser := ctx.Value(userContextKey).(*model.User)
+	stream, err := r.CartServiceClient.WatchUserCart(ctx, &proto.GetUserCartInput{UserId: authUser.ID})
 	if err != nil {
 		return nil, parseGrpcError(err)
 	}
-	return ProtoUserToGql(userResponse.User), nil
+	carts := make(chan *model.Cart, 1)
+	go func() {
+		defer close(carts)
+		for {
+			userCart, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			span, _ := opentracing.StartSpanFromContextWithTracer(ctx, r.Tracer, "CartUpdated")
+			// every update gets its own loaders so that product prices are never
+			// served from a previous update's cache.
+			cart, err := r.buildCart(r.withLoaders(ctx), userCart)
+			if err != nil {
+				ext.Error.Set(span, true)
+				span.LogFields(
+					log.Error(err),
+				)
+				span.Finish()
+				// ending the subscription lets the client subscribe again and
+				// get the current cart, skipping the update would leave it
+				// with a stale one.
+				r.Log.WithError(err).WithField("userId", authUser.ID).Error("unable to build updated cart")
+				return
+			}
+			span.Finish()
+			select {
+			case carts <- cart:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return carts, nil
 }
 
 // CartItem returns generated.CartItemResolver implementation.
@@ -216,9 +993,17 @@ func (r *Resolver) CartItem() generated.CartItemResolver { return &cartItemResol
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// Product returns generated.ProductResolver implementation.
+func (r *Resolver) Product() generated.ProductResolver { return &productResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
 type cartItemResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type productResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
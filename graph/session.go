@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+)
+
+var (
+	responseWriterContextKey ContextKey = "response-writer-context-key"
+	RefreshTokenContextKey   ContextKey = "refresh-token-context-key"
+)
+
+// SessionCookieConfig configures the HttpOnly cookies browser clients can use to
+// keep their jwt and refresh tokens away from javascript. The refresh token
+// cookie is only sent to RefreshPath.
+type SessionCookieConfig struct {
+	Name        string
+	RefreshName string
+	RefreshPath string
+	Domain      string
+	SameSite    http.SameSite
+}
+
+func (c *SessionCookieConfig) cookie(name, path, value string, expiresAt time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   c.Domain,
+		Expires:  expiresAt,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	}
+}
+
+// loginResponse returns the response for a successful login, when the tokens
+// are set as cookies they are left out of the response so that javascript can
+// not read them.
+func (r *Resolver) loginResponse(ctx context.Context, res *proto.LoginResponse, setCookie bool) *model.LoginResponse {
+	loginResponse := ProtoLoginResponseToGql(res)
+	if res.ChallengeId != "" || !setCookie {
+		return loginResponse
+	}
+	writeCookie(ctx, r.SessionCookie.cookie(r.SessionCookie.Name, "/", res.JwtToken, unixTime(res.ExpiresAt)))
+	writeCookie(ctx, r.SessionCookie.cookie(r.SessionCookie.RefreshName, r.SessionCookie.RefreshPath, res.RefreshToken, unixTime(res.RefreshExpiresAt)))
+	loginResponse.JwtToken = nil
+	loginResponse.RefreshToken = nil
+	return loginResponse
+}
+
+func (r *Resolver) clearSessionCookie(ctx context.Context) {
+	for _, cookie := range []*http.Cookie{
+		r.SessionCookie.cookie(r.SessionCookie.Name, "/", "", time.Unix(0, 0)),
+		r.SessionCookie.cookie(r.SessionCookie.RefreshName, r.SessionCookie.RefreshPath, "", time.Unix(0, 0)),
+	} {
+		cookie.MaxAge = -1
+		writeCookie(ctx, cookie)
+	}
+}
+
+// unixTime returns the zero time for unset timestamps, the cookies then
+// expire with the browser session.
+func unixTime(timestamp int64) time.Time {
+	if timestamp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(timestamp, 0)
+}
+
+// ContextWithResponseWriter makes rw available to resolvers that need to set cookies.
+func ContextWithResponseWriter(ctx context.Context, rw http.ResponseWriter) context.Context {
+	return context.WithValue(ctx, responseWriterContextKey, rw)
+}
+
+func writeCookie(ctx context.Context, cookie *http.Cookie) {
+	rw, ok := ctx.Value(responseWriterContextKey).(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	http.SetCookie(rw, cookie)
+}
+
+// ParseBearerToken returns the token in a bearer authorization header value, an
+// empty string is returned when the value does not use the bearer scheme.
+func ParseBearerToken(authorization string) string {
+	parts := strings.SplitN(strings.TrimSpace(authorization), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
@@ -3,25 +3,160 @@ package graph
 import (
 	"html"
 	"strings"
+	"time"
 
+	"github.com/wisdommatt/ecommerce-microservice-public-api/auth"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/model"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
 )
 
 func ProtoUserToGql(user *proto.User) *model.User {
-	return &model.User{
-		ID:       user.Id,
+	gqlUser := &model.User{
+		ID:            user.Id,
+		FullName:      user.FullName,
+		Email:         user.Email,
+		Country:       user.Country,
+		Roles:         []model.Role{},
+		EmailVerified: user.EmailVerified,
+		TotpEnabled:   user.TotpEnabled,
+	}
+	for _, role := range user.GetRoles() {
+		gqlUser.Roles = append(gqlUser.Roles, ProtoRoleToGql(role))
+	}
+	return gqlUser
+}
+
+func AuthClaimsToGqlUser(claims *auth.Claims) *model.User {
+	user := &model.User{
+		ID:            claims.Subject,
+		FullName:      claims.FullName,
+		Email:         claims.Email,
+		Country:       claims.Country,
+		Roles:         []model.Role{},
+		EmailVerified: unpointBool(claims.EmailVerified),
+		TotpEnabled:   unpointBool(claims.TotpEnabled),
+	}
+	for _, role := range claims.Roles {
+		if model.Role(role).IsValid() {
+			user.Roles = append(user.Roles, model.Role(role))
+		}
+	}
+	return user
+}
+
+func ProtoRevokedTokenToAuth(revokedToken *proto.RevokedToken) auth.Revocation {
+	revocation := auth.Revocation{
+		TokenID: revokedToken.TokenId,
+		UserID:  revokedToken.UserId,
+	}
+	if revokedToken.IssuedBefore > 0 {
+		revocation.IssuedBefore = time.Unix(revokedToken.IssuedBefore, 0)
+	}
+	if revokedToken.ExpiresAt > 0 {
+		revocation.ExpiresAt = time.Unix(revokedToken.ExpiresAt, 0)
+	}
+	return revocation
+}
+
+func GqlUpdateProfileToProto(userId string, input *model.UpdateProfile) *proto.UpdateProfileInput {
+	return &proto.UpdateProfileInput{
+		UserId:   userId,
+		FullName: html.EscapeString(strings.TrimSpace(unpointStr(input.FullName))),
+		Country:  html.EscapeString(strings.TrimSpace(unpointStr(input.Country))),
+	}
+}
+
+func ProtoRoleToGql(role proto.Role) model.Role {
+	switch role {
+	case proto.Role_MERCHANT:
+		return model.RoleMerchant
+	case proto.Role_ADMIN:
+		return model.RoleAdmin
+	}
+	return model.RoleCustomer
+}
+
+// GqlUserToMerchant only keeps the fields of the user that are public.
+func GqlUserToMerchant(user *model.User) *model.Merchant {
+	if user == nil {
+		return nil
+	}
+	return &model.Merchant{
+		ID:       user.ID,
 		FullName: user.FullName,
-		Email:    user.Email,
-		Country:  user.Country,
 	}
 }
 
+func ProtoUsersResponseToGqlConnection(res *proto.GetUsersResponse) *model.UserConnection {
+	connection := &model.UserConnection{
+		Edges:      []*model.UserEdge{},
+		PageInfo:   &model.PageInfo{HasNextPage: res.HasNextPage},
+		TotalCount: int(res.TotalCount),
+	}
+	for _, user := range res.GetUsers() {
+		connection.Edges = append(connection.Edges, &model.UserEdge{
+			Cursor: encodeCursor(user.Id),
+			Node:   ProtoUserToGql(user),
+		})
+	}
+	if len(connection.Edges) > 0 {
+		endCursor := connection.Edges[len(connection.Edges)-1].Cursor
+		connection.PageInfo.EndCursor = &endCursor
+	}
+	return connection
+}
+
+// ProtoLoginResponseToGql only returns the challenge when the user service
+// asked for a second factor, the tokens are returned once it is verified.
 func ProtoLoginResponseToGql(res *proto.LoginResponse) *model.LoginResponse {
+	if res.ChallengeId != "" {
+		return &model.LoginResponse{
+			SecondFactorChallenge: ProtoSecondFactorChallengeToGql(res),
+		}
+	}
+	expiresAt := time.Unix(res.ExpiresAt, 0).UTC()
 	return &model.LoginResponse{
-		JwtToken: res.JwtToken,
-		User:     ProtoUserToGql(res.User),
+		JwtToken:     &res.JwtToken,
+		RefreshToken: &res.RefreshToken,
+		ExpiresAt:    &expiresAt,
+		User:         ProtoUserToGql(res.User),
+	}
+}
+
+func ProtoSecondFactorChallengeToGql(res *proto.LoginResponse) *model.SecondFactorChallenge {
+	return &model.SecondFactorChallenge{
+		ChallengeID: res.ChallengeId,
+		ExpiresAt:   time.Unix(res.ChallengeExpiresAt, 0).UTC(),
+	}
+}
+
+func ProtoAPIKeyToGql(apiKey *proto.ApiKey) *model.APIKey {
+	gqlAPIKey := &model.APIKey{
+		ID:        apiKey.Id,
+		Name:      apiKey.Name,
+		Scopes:    []model.APIKeyScope{},
+		CreatedAt: time.Unix(apiKey.CreatedAt, 0).UTC(),
+	}
+	for _, scope := range apiKey.GetScopes() {
+		// scopes are stored as e.g products:write.
+		gqlScope := model.APIKeyScope(strings.ToUpper(strings.ReplaceAll(scope, ":", "_")))
+		if gqlScope.IsValid() {
+			gqlAPIKey.Scopes = append(gqlAPIKey.Scopes, gqlScope)
+		}
+	}
+	if apiKey.LastUsedAt > 0 {
+		lastUsedAt := time.Unix(apiKey.LastUsedAt, 0).UTC()
+		gqlAPIKey.LastUsedAt = &lastUsedAt
+	}
+	return gqlAPIKey
+}
+
+func GqlAPIKeyScopesToProto(scopes []model.APIKeyScope) []string {
+	protoScopes := make([]string, len(scopes))
+	for i, scope := range scopes {
+		protoScopes[i] = strings.ToLower(strings.ReplaceAll(scope.String(), "_", ":"))
 	}
+	return protoScopes
 }
 
 func GqlNewUserToProto(user *model.NewUser) *proto.NewUser {
@@ -53,9 +188,94 @@ func ProtoProductToGql(product *proto.Product) *model.Product {
 		Brand:       product.Brand,
 		Price:       product.Price,
 		ImageURL:    product.ImageUrl,
+		MerchantID:  product.MerchantId,
+	}
+}
+
+func GqlUpdateProductToProto(sku, merchantId string, input *model.UpdateProduct) *proto.UpdateProductInput {
+	update := &proto.UpdateProductInput{
+		Sku:          sku,
+		MerchantId:   merchantId,
+		UpdateFields: []string{},
+	}
+	if input.Name != nil {
+		update.Name = html.EscapeString(*input.Name)
+		update.UpdateFields = append(update.UpdateFields, "name")
+	}
+	if input.Description != nil {
+		update.Description = html.EscapeString(*input.Description)
+		update.UpdateFields = append(update.UpdateFields, "description")
+	}
+	if input.Category != nil {
+		update.Category = html.EscapeString(*input.Category)
+		update.UpdateFields = append(update.UpdateFields, "category")
+	}
+	if input.Brand != nil {
+		update.Brand = html.EscapeString(*input.Brand)
+		update.UpdateFields = append(update.UpdateFields, "brand")
+	}
+	if input.Price != nil {
+		update.Price = *input.Price
+		update.UpdateFields = append(update.UpdateFields, "price")
+	}
+	if input.ImageURL != nil {
+		update.ImageUrl = html.EscapeString(*input.ImageURL)
+		update.UpdateFields = append(update.UpdateFields, "imageUrl")
+	}
+	return update
+}
+
+func ProtoProductsResponseToGqlConnection(res *proto.ListProductsResponse) *model.ProductConnection {
+	connection := &model.ProductConnection{
+		Edges:      []*model.ProductEdge{},
+		PageInfo:   &model.PageInfo{HasNextPage: res.HasNextPage},
+		TotalCount: int(res.TotalCount),
+	}
+	for _, product := range res.GetProducts() {
+		connection.Edges = append(connection.Edges, &model.ProductEdge{
+			Cursor: encodeCursor(product.Sku),
+			Node:   ProtoProductToGql(product),
+		})
+	}
+	if len(connection.Edges) > 0 {
+		endCursor := connection.Edges[len(connection.Edges)-1].Cursor
+		connection.PageInfo.EndCursor = &endCursor
+	}
+	return connection
+}
+
+func GqlProductFilterToProto(filter *model.ProductFilter) *proto.ProductFilter {
+	if filter == nil {
+		return &proto.ProductFilter{}
+	}
+	return &proto.ProductFilter{
+		Category:    html.EscapeString(unpointStr(filter.Category)),
+		Brand:       html.EscapeString(unpointStr(filter.Brand)),
+		MinPrice:    unpointFloat(filter.MinPrice),
+		MaxPrice:    unpointFloat(filter.MaxPrice),
+		HasMinPrice: filter.MinPrice != nil,
+		HasMaxPrice: filter.MaxPrice != nil,
+		Name:        html.EscapeString(strings.TrimSpace(unpointStr(filter.Name))),
 	}
 }
 
+func GqlProductSortToProto(sort *model.ProductSort) proto.ProductSort {
+	if sort == nil {
+		return proto.ProductSort_PRODUCT_SORT_NEWEST
+	}
+	switch *sort {
+	case model.ProductSortPriceAsc:
+		return proto.ProductSort_PRODUCT_SORT_PRICE_ASC
+	case model.ProductSortPriceDesc:
+		return proto.ProductSort_PRODUCT_SORT_PRICE_DESC
+	case model.ProductSortNameAsc:
+		return proto.ProductSort_PRODUCT_SORT_NAME_ASC
+	case model.ProductSortNameDesc:
+		return proto.ProductSort_PRODUCT_SORT_NAME_DESC
+	}
+	return proto.ProductSort_PRODUCT_SORT_NEWEST
+}
+
 func GqlNewCartItemToProto(item *model.NewCartItem, userId string) *proto.NewCartItem {
 	return &proto.NewCartItem{
 		ProductSku: html.EscapeString(item.ProductSku),
@@ -72,9 +292,71 @@ func ProtoCartItemToGql(item *proto.CartItem) *model.CartItem {
 	}
 }
 
+func ProtoCartItemToUnavailableGql(item *proto.CartItem) *model.UnavailableCartItem {
+	return &model.UnavailableCartItem{
+		ID:         item.Id,
+		ProductSku: item.ProductSku,
+		Quantity:   int(item.Quantity),
+	}
+}
+
+func GqlCartToProtoNewOrder(cart *model.Cart, userId, shippingAddress string) *proto.NewOrder {
+	order := &proto.NewOrder{
+		UserId:          userId,
+		Subtotal:        cart.Subtotal,
+		Currency:        cart.Currency,
+		ShippingAddress: html.EscapeString(strings.TrimSpace(shippingAddress)),
+	}
+	for _, item := range cart.Items {
+		order.Items = append(order.Items, &proto.OrderItem{
+			ProductSku:  item.ProductSku,
+			ProductName: item.Product.Name,
+			UnitPrice:   item.Product.Price,
+			Quantity:    int32(item.Quantity),
+		})
+	}
+	return order
+}
+
+func ProtoOrderToGql(order *proto.Order) *model.Order {
+	gqlOrder := &model.Order{
+		ID:              order.Id,
+		Items:           []*model.OrderItem{},
+		Subtotal:        order.Subtotal,
+		Currency:        order.Currency,
+		ShippingAddress: order.ShippingAddress,
+		Status:          order.Status,
+		CreatedAt:       time.Unix(order.CreatedAt, 0).UTC(),
+	}
+	for _, item := range order.GetItems() {
+		gqlOrder.Items = append(gqlOrder.Items, &model.OrderItem{
+			ProductSku:  item.ProductSku,
+			ProductName: item.ProductName,
+			UnitPrice:   item.UnitPrice,
+			Quantity:    int(item.Quantity),
+		})
+	}
+	return gqlOrder
+}
+
 func unpointStr(str *string) string {
 	if str == nil {
 		return ""
 	}
 	return *str
 }
+
+func isEmptyStr(str *string) bool {
+	return str != nil && strings.TrimSpace(*str) == ""
+}
+
+func unpointBool(b *bool) bool {
+	return b != nil && *b
+}
+
+func unpointFloat(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
@@ -154,6 +154,69 @@ func (x *CartItem) GetQuantity() int32 {
 	return 0
 }
 
+type UpdateCartItemInput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId   string `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	ItemId   string `protobuf:"bytes,2,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Quantity int32  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+}
+
+func (x *UpdateCartItemInput) Reset() {
+	*x = UpdateCartItemInput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_files_cart_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateCartItemInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCartItemInput) ProtoMessage() {}
+
+func (x *UpdateCartItemInput) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_files_cart_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCartItemInput.ProtoReflect.Descriptor instead.
+func (*UpdateCartItemInput) Descriptor() ([]byte, []int) {
+	return file_proto_files_cart_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateCartItemInput) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateCartItemInput) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *UpdateCartItemInput) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
 type GetUserCartInput struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -165,7 +228,7 @@ type GetUserCartInput struct {
 func (x *GetUserCartInput) Reset() {
 	*x = GetUserCartInput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_files_cart_proto_msgTypes[2]
+		mi := &file_proto_files_cart_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -178,7 +241,7 @@ func (x *GetUserCartInput) String() string {
 func (*GetUserCartInput) ProtoMessage() {}
 
 func (x *GetUserCartInput) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_files_cart_proto_msgTypes[2]
+	mi := &file_proto_files_cart_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,7 +254,7 @@ func (x *GetUserCartInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserCartInput.ProtoReflect.Descriptor instead.
 func (*GetUserCartInput) Descriptor() ([]byte, []int) {
-	return file_proto_files_cart_proto_rawDescGZIP(), []int{2}
+	return file_proto_files_cart_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetUserCartInput) GetUserId() string {
@@ -206,13 +269,14 @@ type GetUserCartResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Items []*CartItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Items     []*CartItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	UpdatedAt int64       `protobuf:"varint,2,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
 }
 
 func (x *GetUserCartResponse) Reset() {
 	*x = GetUserCartResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_files_cart_proto_msgTypes[3]
+		mi := &file_proto_files_cart_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -225,7 +289,7 @@ func (x *GetUserCartResponse) String() string {
 func (*GetUserCartResponse) ProtoMessage() {}
 
 func (x *GetUserCartResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_files_cart_proto_msgTypes[3]
+	mi := &file_proto_files_cart_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -238,7 +302,7 @@ func (x *GetUserCartResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserCartResponse.ProtoReflect.Descriptor instead.
 func (*GetUserCartResponse) Descriptor() ([]byte, []int) {
-	return file_proto_files_cart_proto_rawDescGZIP(), []int{3}
+	return file_proto_files_cart_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetUserCartResponse) GetItems() []*CartItem {
@@ -248,6 +312,13 @@ func (x *GetUserCartResponse) GetItems() []*CartItem {
 	return nil
 }
 
+func (x *GetUserCartResponse) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
 type RemoveItemsFromCartInput struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -260,7 +331,7 @@ type RemoveItemsFromCartInput struct {
 func (x *RemoveItemsFromCartInput) Reset() {
 	*x = RemoveItemsFromCartInput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_files_cart_proto_msgTypes[4]
+		mi := &file_proto_files_cart_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -273,7 +344,7 @@ func (x *RemoveItemsFromCartInput) String() string {
 func (*RemoveItemsFromCartInput) ProtoMessage() {}
 
 func (x *RemoveItemsFromCartInput) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_files_cart_proto_msgTypes[4]
+	mi := &file_proto_files_cart_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,7 +357,7 @@ func (x *RemoveItemsFromCartInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveItemsFromCartInput.ProtoReflect.Descriptor instead.
 func (*RemoveItemsFromCartInput) Descriptor() ([]byte, []int) {
-	return file_proto_files_cart_proto_rawDescGZIP(), []int{4}
+	return file_proto_files_cart_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *RemoveItemsFromCartInput) GetUserId() string {
@@ -314,7 +385,7 @@ type RemoveItemsFromCartResponse struct {
 func (x *RemoveItemsFromCartResponse) Reset() {
 	*x = RemoveItemsFromCartResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_files_cart_proto_msgTypes[5]
+		mi := &file_proto_files_cart_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -327,7 +398,7 @@ func (x *RemoveItemsFromCartResponse) String() string {
 func (*RemoveItemsFromCartResponse) ProtoMessage() {}
 
 func (x *RemoveItemsFromCartResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_files_cart_proto_msgTypes[5]
+	mi := &file_proto_files_cart_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -340,7 +411,7 @@ func (x *RemoveItemsFromCartResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveItemsFromCartResponse.ProtoReflect.Descriptor instead.
 func (*RemoveItemsFromCartResponse) Descriptor() ([]byte, []int) {
-	return file_proto_files_cart_proto_rawDescGZIP(), []int{5}
+	return file_proto_files_cart_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *RemoveItemsFromCartResponse) GetItems() []*CartItem {
@@ -367,34 +438,49 @@ var file_proto_files_cart_proto_rawDesc = []byte{
 	0x64, 0x75, 0x63, 0x74, 0x53, 0x6b, 0x75, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
 	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12,
 	0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x2a, 0x0a, 0x10, 0x47,
-	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x43, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12,
-	0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x36, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x55, 0x73,
-	0x65, 0x72, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f,
-	0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e,
-	0x43, 0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22,
-	0x4c, 0x0a, 0x18, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x46, 0x72,
-	0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75,
-	0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65,
-	0x72, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x73, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x73, 0x22, 0x3e, 0x0a,
-	0x1b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x46, 0x72, 0x6f, 0x6d,
-	0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x05,
-	0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x43, 0x61,
-	0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x32, 0xbb, 0x01,
-	0x0a, 0x0b, 0x43, 0x61, 0x72, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x24, 0x0a,
-	0x09, 0x41, 0x64, 0x64, 0x54, 0x6f, 0x43, 0x61, 0x72, 0x74, 0x12, 0x0c, 0x2e, 0x4e, 0x65, 0x77,
-	0x43, 0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x1a, 0x09, 0x2e, 0x43, 0x61, 0x72, 0x74, 0x49,
-	0x74, 0x65, 0x6d, 0x12, 0x36, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x43, 0x61,
-	0x72, 0x74, 0x12, 0x11, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x43, 0x61, 0x72, 0x74,
-	0x49, 0x6e, 0x70, 0x75, 0x74, 0x1a, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x43,
-	0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x13, 0x52,
-	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61,
-	0x72, 0x74, 0x12, 0x19, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73,
-	0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x1a, 0x1c, 0x2e,
-	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x43,
-	0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0c, 0x5a, 0x0a, 0x67,
+	0x05, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x61, 0x0a, 0x13, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x70,
+	0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x74,
+	0x65, 0x6d, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d,
+	0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x2a,
+	0x0a, 0x10, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x43, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x70,
+	0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x54, 0x0a, 0x13, 0x47, 0x65,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1f, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x09, 0x2e, 0x43, 0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x4c, 0x0a, 0x18, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x46,
+	0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x73, 0x22, 0x3e,
+	0x0a, 0x1b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x46, 0x72, 0x6f,
+	0x6d, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a,
+	0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x43,
+	0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x32, 0xaa,
+	0x02, 0x0a, 0x0b, 0x43, 0x61, 0x72, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x24,
+	0x0a, 0x09, 0x41, 0x64, 0x64, 0x54, 0x6f, 0x43, 0x61, 0x72, 0x74, 0x12, 0x0c, 0x2e, 0x4e, 0x65,
+	0x77, 0x43, 0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x1a, 0x09, 0x2e, 0x43, 0x61, 0x72, 0x74,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x31, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61,
+	0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43,
+	0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x1a, 0x09, 0x2e, 0x43,
+	0x61, 0x72, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x36, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x43, 0x61, 0x72, 0x74, 0x12, 0x11, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72,
+	0x43, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x1a, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x55,
+	0x73, 0x65, 0x72, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4e, 0x0a, 0x13, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x46, 0x72,
+	0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x12, 0x19, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x70, 0x75,
+	0x74, 0x1a, 0x1c, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x46,
+	0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3a, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x55, 0x73, 0x65, 0x72, 0x43, 0x61, 0x72, 0x74,
+	0x12, 0x11, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x43, 0x61, 0x72, 0x74, 0x49, 0x6e,
+	0x70, 0x75, 0x74, 0x1a, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x43, 0x61, 0x72,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x0c, 0x5a, 0x0a, 0x67,
 	0x72, 0x70, 0x63, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x33,
 }
@@ -411,26 +497,31 @@ func file_proto_files_cart_proto_rawDescGZIP() []byte {
 	return file_proto_files_cart_proto_rawDescData
 }
 
-var file_proto_files_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_files_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_proto_files_cart_proto_goTypes = []interface{}{
 	(*NewCartItem)(nil),                 // 0: NewCartItem
 	(*CartItem)(nil),                    // 1: CartItem
-	(*GetUserCartInput)(nil),            // 2: GetUserCartInput
-	(*GetUserCartResponse)(nil),         // 3: GetUserCartResponse
-	(*RemoveItemsFromCartInput)(nil),    // 4: RemoveItemsFromCartInput
-	(*RemoveItemsFromCartResponse)(nil), // 5: RemoveItemsFromCartResponse
+	(*UpdateCartItemInput)(nil),         // 2: UpdateCartItemInput
+	(*GetUserCartInput)(nil),            // 3: GetUserCartInput
+	(*GetUserCartResponse)(nil),         // 4: GetUserCartResponse
+	(*RemoveItemsFromCartInput)(nil),    // 5: RemoveItemsFromCartInput
+	(*RemoveItemsFromCartResponse)(nil), // 6: RemoveItemsFromCartResponse
 }
 var file_proto_files_cart_proto_depIdxs = []int32{
 	1, // 0: GetUserCartResponse.items:type_name -> CartItem
 	1, // 1: RemoveItemsFromCartResponse.items:type_name -> CartItem
 	0, // 2: CartService.AddToCart:input_type -> NewCartItem
-	2, // 3: CartService.GetUserCart:input_type -> GetUserCartInput
-	4, // 4: CartService.RemoveItemsFromCart:input_type -> RemoveItemsFromCartInput
-	1, // 5: CartService.AddToCart:output_type -> CartItem
-	3, // 6: CartService.GetUserCart:output_type -> GetUserCartResponse
-	5, // 7: CartService.RemoveItemsFromCart:output_type -> RemoveItemsFromCartResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
+	2, // 3: CartService.UpdateCartItem:input_type -> UpdateCartItemInput
+	3, // 4: CartService.GetUserCart:input_type -> GetUserCartInput
+	5, // 5: CartService.RemoveItemsFromCart:input_type -> RemoveItemsFromCartInput
+	3, // 6: CartService.WatchUserCart:input_type -> GetUserCartInput
+	1, // 7: CartService.AddToCart:output_type -> CartItem
+	1, // 8: CartService.UpdateCartItem:output_type -> CartItem
+	4, // 9: CartService.GetUserCart:output_type -> GetUserCartResponse
+	6, // 10: CartService.RemoveItemsFromCart:output_type -> RemoveItemsFromCartResponse
+	4, // 11: CartService.WatchUserCart:output_type -> GetUserCartResponse
+	7, // [7:12] is the sub-list for method output_type
+	2, // [2:7] is the sub-list for method input_type
 	2, // [2:2] is the sub-list for extension type_name
 	2, // [2:2] is the sub-list for extension extendee
 	0, // [0:2] is the sub-list for field type_name
@@ -467,7 +558,7 @@ func file_proto_files_cart_proto_init() {
 			}
 		}
 		file_proto_files_cart_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetUserCartInput); i {
+			switch v := v.(*UpdateCartItemInput); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -479,7 +570,7 @@ func file_proto_files_cart_proto_init() {
 			}
 		}
 		file_proto_files_cart_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetUserCartResponse); i {
+			switch v := v.(*GetUserCartInput); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -491,7 +582,7 @@ func file_proto_files_cart_proto_init() {
 			}
 		}
 		file_proto_files_cart_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveItemsFromCartInput); i {
+			switch v := v.(*GetUserCartResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -503,6 +594,18 @@ func file_proto_files_cart_proto_init() {
 			}
 		}
 		file_proto_files_cart_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveItemsFromCartInput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_files_cart_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveItemsFromCartResponse); i {
 			case 0:
 				return &v.state
@@ -521,7 +624,7 @@ func file_proto_files_cart_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_files_cart_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -18,9 +18,13 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CartServiceClient interface {
+	// AddToCart atomically increments the quantity of the user's item for the
+	// product when there is one, instead of adding a second item.
 	AddToCart(ctx context.Context, in *NewCartItem, opts ...grpc.CallOption) (*CartItem, error)
+	UpdateCartItem(ctx context.Context, in *UpdateCartItemInput, opts ...grpc.CallOption) (*CartItem, error)
 	GetUserCart(ctx context.Context, in *GetUserCartInput, opts ...grpc.CallOption) (*GetUserCartResponse, error)
 	RemoveItemsFromCart(ctx context.Context, in *RemoveItemsFromCartInput, opts ...grpc.CallOption) (*RemoveItemsFromCartResponse, error)
+	WatchUserCart(ctx context.Context, in *GetUserCartInput, opts ...grpc.CallOption) (CartService_WatchUserCartClient, error)
 }
 
 type cartServiceClient struct {
@@ -40,6 +44,15 @@ func (c *cartServiceClient) AddToCart(ctx context.Context, in *NewCartItem, opts
 	return out, nil
 }
 
+func (c *cartServiceClient) UpdateCartItem(ctx context.Context, in *UpdateCartItemInput, opts ...grpc.CallOption) (*CartItem, error) {
+	out := new(CartItem)
+	err := c.cc.Invoke(ctx, "/CartService/UpdateCartItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cartServiceClient) GetUserCart(ctx context.Context, in *GetUserCartInput, opts ...grpc.CallOption) (*GetUserCartResponse, error) {
 	out := new(GetUserCartResponse)
 	err := c.cc.Invoke(ctx, "/CartService/GetUserCart", in, out, opts...)
@@ -58,13 +71,49 @@ func (c *cartServiceClient) RemoveItemsFromCart(ctx context.Context, in *RemoveI
 	return out, nil
 }
 
+func (c *cartServiceClient) WatchUserCart(ctx context.Context, in *GetUserCartInput, opts ...grpc.CallOption) (CartService_WatchUserCartClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CartService_ServiceDesc.Streams[0], "/CartService/WatchUserCart", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cartServiceWatchUserCartClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CartService_WatchUserCartClient interface {
+	Recv() (*GetUserCartResponse, error)
+	grpc.ClientStream
+}
+
+type cartServiceWatchUserCartClient struct {
+	grpc.ClientStream
+}
+
+func (x *cartServiceWatchUserCartClient) Recv() (*GetUserCartResponse, error) {
+	m := new(GetUserCartResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // CartServiceServer is the server API for CartService service.
 // All implementations must embed UnimplementedCartServiceServer
 // for forward compatibility
 type CartServiceServer interface {
+	// AddToCart atomically increments the quantity of the user's item for the
+	// product when there is one, instead of adding a second item.
 	AddToCart(context.Context, *NewCartItem) (*CartItem, error)
+	UpdateCartItem(context.Context, *UpdateCartItemInput) (*CartItem, error)
 	GetUserCart(context.Context, *GetUserCartInput) (*GetUserCartResponse, error)
 	RemoveItemsFromCart(context.Context, *RemoveItemsFromCartInput) (*RemoveItemsFromCartResponse, error)
+	WatchUserCart(*GetUserCartInput, CartService_WatchUserCartServer) error
 	mustEmbedUnimplementedCartServiceServer()
 }
 
@@ -75,12 +124,18 @@ type UnimplementedCartServiceServer struct {
 func (UnimplementedCartServiceServer) AddToCart(context.Context, *NewCartItem) (*CartItem, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddToCart not implemented")
 }
+func (UnimplementedCartServiceServer) UpdateCartItem(context.Context, *UpdateCartItemInput) (*CartItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCartItem not implemented")
+}
 func (UnimplementedCartServiceServer) GetUserCart(context.Context, *GetUserCartInput) (*GetUserCartResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserCart not implemented")
 }
 func (UnimplementedCartServiceServer) RemoveItemsFromCart(context.Context, *RemoveItemsFromCartInput) (*RemoveItemsFromCartResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveItemsFromCart not implemented")
 }
+func (UnimplementedCartServiceServer) WatchUserCart(*GetUserCartInput, CartService_WatchUserCartServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUserCart not implemented")
+}
 func (UnimplementedCartServiceServer) mustEmbedUnimplementedCartServiceServer() {}
 
 // UnsafeCartServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -112,6 +167,24 @@ func _CartService_AddToCart_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_UpdateCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCartItemInput)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).UpdateCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/CartService/UpdateCartItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).UpdateCartItem(ctx, req.(*UpdateCartItemInput))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CartService_GetUserCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetUserCartInput)
 	if err := dec(in); err != nil {
@@ -148,6 +221,27 @@ func _CartService_RemoveItemsFromCart_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_WatchUserCart_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetUserCartInput)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CartServiceServer).WatchUserCart(m, &cartServiceWatchUserCartServer{stream})
+}
+
+type CartService_WatchUserCartServer interface {
+	Send(*GetUserCartResponse) error
+	grpc.ServerStream
+}
+
+type cartServiceWatchUserCartServer struct {
+	grpc.ServerStream
+}
+
+func (x *cartServiceWatchUserCartServer) Send(m *GetUserCartResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // CartService_ServiceDesc is the grpc.ServiceDesc for CartService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -159,6 +253,10 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddToCart",
 			Handler:    _CartService_AddToCart_Handler,
 		},
+		{
+			MethodName: "UpdateCartItem",
+			Handler:    _CartService_UpdateCartItem_Handler,
+		},
 		{
 			MethodName: "GetUserCart",
 			Handler:    _CartService_GetUserCart_Handler,
@@ -168,6 +266,12 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _CartService_RemoveItemsFromCart_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUserCart",
+			Handler:       _CartService_WatchUserCart_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "proto-files/cart.proto",
 }
@@ -5,21 +5,29 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
 	"github.com/joho/godotenv"
 	otgrpc "github.com/opentracing-contrib/go-grpc"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"github.com/uber/jaeger-client-go"
 	"github.com/uber/jaeger-client-go/config"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/auth"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/graph"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/graph/generated"
 	"github.com/wisdommatt/ecommerce-microservice-public-api/grpc/proto"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/notification"
+	"github.com/wisdommatt/ecommerce-microservice-public-api/ratelimit"
 	"google.golang.org/grpc"
 )
 
@@ -44,8 +52,14 @@ func main() {
 	userServiceClientConn, err := grpc.Dial(
 		os.Getenv("USER_SERVICE_ADDR"),
 		grpc.WithInsecure(),
-		grpc.WithUnaryInterceptor(otgrpc.OpenTracingClientInterceptor(tracer)),
-		grpc.WithStreamInterceptor(otgrpc.OpenTracingStreamClientInterceptor(tracer)),
+		grpc.WithChainUnaryInterceptor(
+			otgrpc.OpenTracingClientInterceptor(tracer),
+			graph.UnaryClientMetadataInterceptor(),
+		),
+		grpc.WithChainStreamInterceptor(
+			otgrpc.OpenTracingStreamClientInterceptor(tracer),
+			graph.StreamClientMetadataInterceptor(),
+		),
 	)
 	if err != nil {
 		log.WithError(err).WithField("userServiceAddr", os.Getenv("USER_SERVICE_ADDR")).
@@ -56,8 +70,14 @@ func main() {
 	productServiceClientConn, err := grpc.Dial(
 		os.Getenv("PRODUCT_SERVICE_ADDR"),
 		grpc.WithInsecure(),
-		grpc.WithUnaryInterceptor(otgrpc.OpenTracingClientInterceptor(tracer)),
-		grpc.WithStreamInterceptor(otgrpc.OpenTracingStreamClientInterceptor(tracer)),
+		grpc.WithChainUnaryInterceptor(
+			otgrpc.OpenTracingClientInterceptor(tracer),
+			graph.UnaryClientMetadataInterceptor(),
+		),
+		grpc.WithChainStreamInterceptor(
+			otgrpc.OpenTracingStreamClientInterceptor(tracer),
+			graph.StreamClientMetadataInterceptor(),
+		),
 	)
 	if err != nil {
 		log.WithError(err).WithField("productServiceAddr", os.Getenv("PRODUCT_SERVICE_ADDR")).
@@ -68,8 +88,14 @@ func main() {
 	cartServiceClientConn, err := grpc.Dial(
 		os.Getenv("CART_SERVICE_ADDR"),
 		grpc.WithInsecure(),
-		grpc.WithUnaryInterceptor(otgrpc.OpenTracingClientInterceptor(tracer)),
-		grpc.WithStreamInterceptor(otgrpc.OpenTracingStreamClientInterceptor(tracer)),
+		grpc.WithChainUnaryInterceptor(
+			otgrpc.OpenTracingClientInterceptor(tracer),
+			graph.UnaryClientMetadataInterceptor(),
+		),
+		grpc.WithChainStreamInterceptor(
+			otgrpc.OpenTracingStreamClientInterceptor(tracer),
+			graph.StreamClientMetadataInterceptor(),
+		),
 	)
 	if err != nil {
 		log.WithError(err).WithField("cartServiceAddr", os.Getenv("CART_SERVICE_ADDR")).
@@ -77,18 +103,94 @@ func main() {
 	}
 	cartServiceClient := proto.NewCartServiceClient(cartServiceClientConn)
 
+	orderServiceClientConn, err := grpc.Dial(
+		os.Getenv("ORDER_SERVICE_ADDR"),
+		grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(
+			otgrpc.OpenTracingClientInterceptor(tracer),
+			graph.UnaryClientMetadataInterceptor(),
+		),
+		grpc.WithChainStreamInterceptor(
+			otgrpc.OpenTracingStreamClientInterceptor(tracer),
+			graph.StreamClientMetadataInterceptor(),
+		),
+	)
+	if err != nil {
+		log.WithError(err).WithField("orderServiceAddr", os.Getenv("ORDER_SERVICE_ADDR")).
+			Fatal("an error occured while connecting to order service")
+	}
+	orderServiceClient := proto.NewOrderServiceClient(orderServiceClientConn)
+
+	sessionCookie := sessionCookieConfig()
+	authenticator := &graph.Authenticator{
+		UserService: userServiceClient,
+		Verifier:    mustInitJWTVerifier(log, userServiceClient),
+	}
+
+	production := mustLoadProfile(log) == productionProfile
 	config := generated.Config{Resolvers: &graph.Resolver{
 		Tracer:               initTracer("graphql-api"),
 		UserServiceClient:    userServiceClient,
 		ProductServiceClient: productServiceClient,
 		CartServiceClient:    cartServiceClient,
+		OrderServiceClient:   orderServiceClient,
+		Authenticator:        authenticator,
+		IdentityProviders:    mustInitIdentityProviders(log),
+		LoginGuard:           initLoginGuard(log),
+		SessionCookie:        sessionCookie,
+		Notifier:             mustInitNotifier(log, production),
+		PasswordResetURL:     os.Getenv("PASSWORD_RESET_URL"),
+		EmailVerificationURL: os.Getenv("EMAIL_VERIFICATION_URL"),
+		Currency:             os.Getenv("CURRENCY"),
+		Log:                  log,
 	}}
-	config.Directives.IsAuthenticated = graph.IsAuthenticated(userServiceClient)
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(config))
+	config.Directives.IsAuthenticated = graph.IsAuthenticated(authenticator)
+	config.Directives.HasRole = graph.HasRole(authenticator)
+	config.Directives.RequiresVerifiedEmail = graph.RequiresVerifiedEmail(authenticator)
+	config.Complexity = graph.Complexity()
+	srv := handler.New(generated.NewExecutableSchema(config))
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              graph.WebsocketInitFunc(authenticator),
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.Use(graph.Dataloaders{ProductService: productServiceClient, UserService: userServiceClient})
+	if !production {
+		srv.Use(extension.Introspection{})
+	}
+	usePersistedQueries(log, srv)
+	if maxAge := mustEnvDuration(log, "APQ_PUBLIC_MAX_AGE"); maxAge > 0 {
+		srv.Use(graph.PublicCache{MaxAge: maxAge})
+	}
+	if maxDepth := mustEnvInt(log, "MAX_QUERY_DEPTH"); maxDepth > 0 {
+		srv.Use(graph.DepthLimit{MaxDepth: maxDepth})
+	}
+	if maxComplexity := mustEnvInt(log, "MAX_QUERY_COMPLEXITY"); maxComplexity > 0 {
+		srv.Use(extension.FixedComplexityLimit(maxComplexity))
+	}
+	if rateLimit := mustInitRateLimit(log, authenticator); rateLimit != nil {
+		srv.Use(rateLimit)
+	}
 
 	router := chi.NewRouter()
-	router.Use(addJwtToHTTPContext)
-	router.Handle("/graphql/playground", playground.Handler("GraphQL playground", "/graphql/query"))
+	if os.Getenv("TRUST_PROXY_HEADERS") == "true" {
+		router.Use(middleware.RealIP)
+	}
+	router.Use(middleware.RequestID)
+	router.Use(graph.RequestMetadataMiddleware)
+	router.Use(addJwtToHTTPContext(sessionCookie))
+	if production {
+		router.Use(securityHeaders)
+		router.Use(limitRequestBody(int64(mustEnvInt(log, "MAX_REQUEST_BODY_BYTES"))))
+	}
+	router.Use(graph.CacheControlMiddleware(sessionCookie.Name))
+	if !production {
+		router.Handle("/graphql/playground", playground.Handler("GraphQL playground", "/graphql/query"))
+	}
 	router.Handle("/graphql/query", srv)
 	httpServer := &http.Server{
 		Addr:         ":" + port,
@@ -97,7 +199,11 @@ func main() {
 		ReadTimeout:  5 * time.Second,
 	}
 
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
+	if production {
+		log.Printf("serving graphql on port %s", port)
+	} else {
+		log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
+	}
 	log.Fatal(httpServer.ListenAndServe())
 }
 
@@ -108,6 +214,204 @@ func mustLoadDotenv(log *logrus.Logger) {
 	}
 }
 
+const (
+	developmentProfile = "development"
+	productionProfile  = "production"
+)
+
+// mustLoadProfile returns the APP_ENV profile, production disables the
+// playground and introspection, caps request bodies, adds security headers and
+// refuses the log notifier.
+func mustLoadProfile(log *logrus.Logger) string {
+	profile := os.Getenv("APP_ENV")
+	if profile == "" {
+		profile = developmentProfile
+	}
+	if profile != developmentProfile && profile != productionProfile {
+		log.WithField("APP_ENV", profile).Fatal("APP_ENV must be either development or production")
+	}
+	log.WithField("profile", profile).Info("loaded app profile")
+	return profile
+}
+
+// mustInitJWTVerifier returns nil when no public key or jwks url is configured,
+// tokens are then always verified by the user service.
+func mustInitJWTVerifier(log *logrus.Logger, userServiceClient proto.UserServiceClient) *auth.Verifier {
+	cfg := auth.Config{
+		PublicKeyFile:          os.Getenv("JWT_PUBLIC_KEY_FILE"),
+		JWKSURL:                os.Getenv("JWT_JWKS_URL"),
+		Issuer:                 os.Getenv("JWT_ISSUER"),
+		Revocations:            graph.RevokedTokens(userServiceClient),
+		RevocationSyncInterval: mustEnvDuration(log, "JWT_REVOCATION_SYNC_INTERVAL"),
+	}
+	if cfg.PublicKeyFile == "" && cfg.JWKSURL == "" {
+		return nil
+	}
+	if interval := os.Getenv("JWT_JWKS_REFRESH_INTERVAL"); interval != "" {
+		refreshInterval, err := time.ParseDuration(interval)
+		if err != nil {
+			log.WithError(err).WithField("refreshInterval", interval).Fatal("invalid jwks refresh interval")
+		}
+		cfg.RefreshInterval = refreshInterval
+	}
+	verifier, err := auth.NewVerifier(context.Background(), cfg, log)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			"publicKeyFile": cfg.PublicKeyFile,
+			"jwksUrl":       cfg.JWKSURL,
+		}).Fatal("unable to initialize jwt verifier")
+	}
+	return verifier
+}
+
+// mustEnvDuration returns the duration value of the environment variable key,
+// zero is returned when it is not set.
+func mustEnvDuration(log *logrus.Logger, key string) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.WithError(err).WithField(key, value).Fatal("invalid duration environment variable")
+	}
+	return d
+}
+
+// mustInitIdentityProviders configures the providers listed in OIDC_PROVIDERS,
+// each provider is configured with OIDC_<NAME>_* variables.
+func mustInitIdentityProviders(log *logrus.Logger) auth.Providers {
+	providers := auth.Providers{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		cfg := auth.ProviderConfig{
+			Name:         name,
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			TokenURL:     os.Getenv(prefix + "TOKEN_URL"),
+			UserInfoURL:  os.Getenv(prefix + "USERINFO_URL"),
+			JWKSURL:      os.Getenv(prefix + "JWKS_URL"),
+		}
+		for _, uri := range strings.Split(os.Getenv(prefix+"REDIRECT_URIS"), ",") {
+			if uri = strings.TrimSpace(uri); uri != "" {
+				cfg.RedirectURIs = append(cfg.RedirectURIs, uri)
+			}
+		}
+		provider, err := auth.NewProvider(context.Background(), cfg)
+		if err != nil {
+			log.WithError(err).WithField("provider", name).Fatal("unable to initialize identity provider")
+		}
+		providers[name] = provider
+	}
+	return providers
+}
+
+// initLoginGuard configures the throttling of failed logins, ip addresses are
+// given more attempts than emails since clients can share one behind a nat.
+func initLoginGuard(log *logrus.Logger) *auth.LoginGuard {
+	policy := auth.LoginPolicy{
+		FreeAttempts:     3,
+		BaseDelay:        mustEnvDuration(log, "LOGIN_BACKOFF_BASE"),
+		MaxDelay:         mustEnvDuration(log, "LOGIN_BACKOFF_MAX"),
+		LockoutThreshold: mustEnvInt(log, "LOGIN_EMAIL_LOCKOUT_THRESHOLD"),
+		LockoutDuration:  mustEnvDuration(log, "LOGIN_LOCKOUT_DURATION"),
+		ResetAfter:       mustEnvDuration(log, "LOGIN_FAILURE_RESET"),
+	}
+	ipPolicy := policy
+	ipPolicy.FreeAttempts = 10
+	ipPolicy.LockoutThreshold = mustEnvInt(log, "LOGIN_IP_LOCKOUT_THRESHOLD")
+	return auth.NewLoginGuard(policy, ipPolicy)
+}
+
+// usePersistedQueries enables automatic persisted queries, the operations in the
+// PERSISTED_QUERY_MANIFEST file are registered up front. Only the operations in
+// the manifest are executed when PERSISTED_QUERY_STRICT is true.
+func usePersistedQueries(log *logrus.Logger, srv *handler.Server) {
+	var manifest graph.PersistedQueryManifest
+	if filename := os.Getenv("PERSISTED_QUERY_MANIFEST"); filename != "" {
+		var err error
+		manifest, err = graph.LoadPersistedQueryManifest(filename)
+		if err != nil {
+			log.WithError(err).WithField("manifest", filename).Fatal("unable to load persisted query manifest")
+		}
+		log.WithField("operations", len(manifest)).Info("loaded persisted query manifest")
+	}
+	if os.Getenv("PERSISTED_QUERY_STRICT") == "true" {
+		if len(manifest) == 0 {
+			log.Fatal("strict persisted queries require a manifest with atleast one operation")
+		}
+		srv.Use(graph.PersistedQueryAllowlist{Manifest: manifest})
+		return
+	}
+	cacheSize := mustEnvInt(log, "APQ_CACHE_SIZE")
+	if cacheSize <= 0 {
+		cacheSize = 100
+	}
+	srv.Use(extension.AutomaticPersistedQuery{Cache: manifest.Cache(lru.New(cacheSize))})
+}
+
+// mustInitRateLimit configures the per client rate limits, nil is returned when
+// no budget has been configured.
+func mustInitRateLimit(log *logrus.Logger, authenticator *graph.Authenticator) *graph.RateLimit {
+	rateLimit := &graph.RateLimit{
+		Store:         ratelimit.NewMemoryStore(),
+		Authenticator: authenticator,
+	}
+	if value := os.Getenv("RATE_LIMIT_DEFAULT"); value != "" {
+		budget, err := ratelimit.ParseBudget(value)
+		if err != nil {
+			log.WithError(err).Fatal("invalid default rate limit")
+		}
+		rateLimit.Default = budget
+	}
+	fields, err := ratelimit.ParseBudgets(os.Getenv("RATE_LIMIT_FIELDS"))
+	if err != nil {
+		log.WithError(err).Fatal("invalid field rate limits")
+	}
+	rateLimit.Fields = fields
+	if rateLimit.Default.Limit == 0 && len(rateLimit.Fields) == 0 {
+		return nil
+	}
+	return rateLimit
+}
+
+// mustEnvInt returns the integer value of the environment variable key,
+// zero is returned when it is not set.
+func mustEnvInt(log *logrus.Logger, key string) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		log.WithError(err).WithField(key, value).Fatal("invalid integer environment variable")
+	}
+	return i
+}
+
+// mustInitNotifier returns the NOTIFIER notifier, the log notifier writes the
+// password reset and verification links to the logs so it is refused in
+// production.
+func mustInitNotifier(log *logrus.Logger, production bool) notification.Notifier {
+	switch notifier := os.Getenv("NOTIFIER"); notifier {
+	case "file":
+		return &notification.FileNotifier{Dir: os.Getenv("NOTIFIER_FILE_DIR")}
+	case "", "log":
+		if production {
+			log.WithField("NOTIFIER", notifier).Fatal("the log notifier can not be used in production")
+		}
+		return &notification.LogNotifier{Log: log}
+	default:
+		log.WithField("NOTIFIER", notifier).Fatal("NOTIFIER must be either log or file")
+		return nil
+	}
+}
+
 func initTracer(serviceName string) opentracing.Tracer {
 	return initJaegerTracer(serviceName)
 }
@@ -127,11 +431,87 @@ func initJaegerTracer(serviceName string) opentracing.Tracer {
 	return tracer
 }
 
-func addJwtToHTTPContext(next http.Handler) http.Handler {
+func sessionCookieConfig() *graph.SessionCookieConfig {
+	cfg := &graph.SessionCookieConfig{
+		Name:        os.Getenv("SESSION_COOKIE_NAME"),
+		RefreshPath: "/graphql/query",
+		Domain:      os.Getenv("SESSION_COOKIE_DOMAIN"),
+		SameSite:    http.SameSiteLaxMode,
+	}
+	if cfg.Name == "" {
+		cfg.Name = "session"
+	}
+	cfg.RefreshName = cfg.Name + "_refresh"
+	switch strings.ToLower(os.Getenv("SESSION_COOKIE_SAMESITE")) {
+	case "strict":
+		cfg.SameSite = http.SameSiteStrictMode
+	case "none":
+		cfg.SameSite = http.SameSiteNoneMode
+	}
+	return cfg
+}
+
+// addJwtToHTTPContext reads the jwt token from the bearer authorization header,
+// falling back to the session cookie for browser clients.
+func addJwtToHTTPContext(sessionCookie *graph.SessionCookieConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			// browsers send cookies with cross-site form posts, they are only
+			// used when the request has a header browsers can only set on
+			// same-origin requests (or after a cors preflight). Websocket
+			// upgrades are protected by the upgrader's origin check.
+			useCookies := r.Header.Get("X-Requested-With") != "" || isWebsocketUpgrade(r)
+			jwtToken := graph.ParseBearerToken(r.Header.Get("Authorization"))
+			if jwtToken == "" && useCookies {
+				if cookie, err := r.Cookie(sessionCookie.Name); err == nil {
+					jwtToken = cookie.Value
+				}
+			}
+			ctx := context.WithValue(r.Context(), graph.JwtContextKey, jwtToken)
+			if cookie, err := r.Cookie(sessionCookie.RefreshName); err == nil && useCookies {
+				ctx = context.WithValue(ctx, graph.RefreshTokenContextKey, cookie.Value)
+			}
+			ctx = context.WithValue(ctx, graph.ApiKeyContextKey, r.Header.Get("X-API-Key"))
+			ctx = graph.ContextWithUserCache(ctx)
+			ctx = graph.ContextWithResponseWriter(ctx, rw)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
+
+func isWebsocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// securityHeaders sets the standard security headers, the api only serves
+// json so nothing is allowed to be loaded or framed from its responses.
+func securityHeaders(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		authorizationHeader := r.Header.Get("Authorization")
-		jwtToken := strings.ReplaceAll(authorizationHeader, "Bearer ", "")
-		ctx := context.WithValue(r.Context(), graph.JwtContextKey, jwtToken)
-		next.ServeHTTP(rw, r.WithContext(ctx))
+		headers := rw.Header()
+		headers.Set("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+		headers.Set("X-Content-Type-Options", "nosniff")
+		headers.Set("X-Frame-Options", "DENY")
+		headers.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+		headers.Set("Referrer-Policy", "no-referrer")
+		headers.Set("Cross-Origin-Resource-Policy", "same-site")
+		next.ServeHTTP(rw, r)
 	})
 }
+
+// limitRequestBody rejects request bodies larger than maxBytes, bodies without
+// a content length are cut off once they exceed it.
+func limitRequestBody(maxBytes int64) func(http.Handler) http.Handler {
+	if maxBytes <= 0 {
+		maxBytes = 1 << 20
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(rw, r.Body, maxBytes)
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
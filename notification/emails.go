@@ -0,0 +1,27 @@
+package notification
+
+import "fmt"
+
+// PasswordResetEmail returns the email that lets a user choose a new password through link.
+func PasswordResetEmail(to, fullName, link string) Email {
+	return Email{
+		To:      to,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password, use the link below to choose a new one:\n\n%s\n\nIf you did not make this request you can safely ignore this email.",
+			fullName, link,
+		),
+	}
+}
+
+// EmailVerificationEmail returns the email that lets a user confirm their email address through link.
+func EmailVerificationEmail(to, fullName, link string) Email {
+	return Email{
+		To:      to,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm that this is your email address by opening the link below:\n\n%s",
+			fullName, link,
+		),
+	}
+}
@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Email is a message sent to a user's email address.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers emails to users, implementations backed by a real email
+// provider can be plugged in without touching the resolvers.
+type Notifier interface {
+	SendEmail(ctx context.Context, email Email) error
+}
+
+// LogNotifier writes emails to the logger instead of sending them, it is meant for local runs.
+type LogNotifier struct {
+	Log *logrus.Logger
+}
+
+func (n *LogNotifier) SendEmail(ctx context.Context, email Email) error {
+	n.Log.WithFields(logrus.Fields{
+		"to":      email.To,
+		"subject": email.Subject,
+		"body":    email.Body,
+	}).Info("email notification")
+	return nil
+}
+
+// FileNotifier writes every email to a new file in Dir, it is meant for local runs.
+type FileNotifier struct {
+	Dir string
+}
+
+func (n *FileNotifier) SendEmail(ctx context.Context, email Email) error {
+	if err := os.MkdirAll(n.Dir, 0o755); err != nil {
+		return err
+	}
+	filename := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(email.To))
+	content := fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s\r\n", email.To, email.Subject, email.Body)
+	return ioutil.WriteFile(filepath.Join(n.Dir, filename), []byte(content), 0o644)
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Budget is the number of requests allowed in every window.
+type Budget struct {
+	Limit  int
+	Window time.Duration
+}
+
+// ParseBudget parses budgets written as <limit>/<window>, e.g 5/1m.
+func ParseBudget(value string) (Budget, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	if len(parts) != 2 {
+		return Budget{}, fmt.Errorf("invalid budget %q, expected <limit>/<window>", value)
+	}
+	limit, err := strconv.Atoi(parts[0])
+	if err != nil || limit < 1 {
+		return Budget{}, fmt.Errorf("invalid budget limit %q", parts[0])
+	}
+	window, err := time.ParseDuration(parts[1])
+	if err != nil || window <= 0 {
+		return Budget{}, fmt.Errorf("invalid budget window %q", parts[1])
+	}
+	return Budget{Limit: limit, Window: window}, nil
+}
+
+// ParseBudgets parses a comma separated list of <name>=<limit>/<window> budgets,
+// e.g authLogin=5/1m,createUser=3/1h.
+func ParseBudgets(value string) (map[string]Budget, error) {
+	budgets := map[string]Budget{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid budget %q, expected <name>=<limit>/<window>", entry)
+		}
+		budget, err := ParseBudget(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		budgets[strings.TrimSpace(parts[0])] = budget
+	}
+	return budgets, nil
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Result is the state of a key's budget after a call to Store.Take.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time left until the budget is refilled.
+	Reset time.Duration
+}
+
+// Request asks for N units of Key's Budget.
+type Request struct {
+	Key    string
+	N      int
+	Budget Budget
+}
+
+// Store keeps track of how much of a budget each key has used, implementations
+// backed by a shared store (e.g redis) can be plugged in so that every instance
+// of the api enforces the same budgets.
+type Store interface {
+	// Take uses the units of every request atomically, nothing is taken when
+	// any of the budgets does not have enough units left. The results are in
+	// the order of the requests.
+	Take(ctx context.Context, requests ...Request) ([]Result, error)
+}
+
+type window struct {
+	used    int
+	resetAt time.Time
+}
+
+// MemoryStore is a fixed window Store that keeps the budgets in memory, the
+// budgets are not shared between instances of the api.
+type MemoryStore struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		windows:   map[string]*window{},
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, requests ...Request) ([]Result, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+	results := make([]Result, len(requests))
+	windows := make([]*window, len(requests))
+	allowed := true
+	for i, req := range requests {
+		w, ok := s.windows[req.Key]
+		if !ok || !now.Before(w.resetAt) {
+			w = &window{resetAt: now.Add(req.Budget.Window)}
+			s.windows[req.Key] = w
+		}
+		windows[i] = w
+		results[i] = Result{
+			Allowed: w.used+req.N <= req.Budget.Limit,
+			Limit:   req.Budget.Limit,
+			Reset:   w.resetAt.Sub(now),
+		}
+		allowed = allowed && results[i].Allowed
+	}
+	for i, req := range requests {
+		if allowed {
+			windows[i].used += req.N
+		}
+		results[i].Remaining = req.Budget.Limit - windows[i].used
+	}
+	return results, nil
+}
+
+// sweep removes the expired windows atmost once a minute so that keys that
+// are no longer used do not grow the store forever.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	for key, w := range s.windows {
+		if !now.Before(w.resetAt) {
+			delete(s.windows, key)
+		}
+	}
+	s.lastSweep = now
+}